@@ -24,9 +24,52 @@ const _ = grpc.SupportPackageIsVersion7
 type PostsClient interface {
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
 	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Post, error)
 	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Post, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Submit sends a draft to review.
+	Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*Post, error)
+	// Publish makes a draft or a reviewed post public.
+	Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*Post, error)
+	// Unpublish turns a published or archived post back into a draft.
+	Unpublish(ctx context.Context, in *UnpublishRequest, opts ...grpc.CallOption) (*Post, error)
+	Archive(ctx context.Context, in *ArchiveRequest, opts ...grpc.CallOption) (*Post, error)
+	// Schedule publishes a draft or a reviewed post at publish_at.
+	Schedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Post, error)
+	Reschedule(ctx context.Context, in *RescheduleRequest, opts ...grpc.CallOption) (*Post, error)
+	CancelSchedule(ctx context.Context, in *CancelScheduleRequest, opts ...grpc.CallOption) (*Post, error)
+	// ListTrash lists deleted posts, most recently deleted first.
+	ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error)
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*Post, error)
+	// Purge permanently removes a post from the trash.
+	Purge(ctx context.Context, in *PurgeRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	ListRevisions(ctx context.Context, in *ListRevisionsRequest, opts ...grpc.CallOption) (*ListRevisionsResponse, error)
+	GetRevision(ctx context.Context, in *GetRevisionRequest, opts ...grpc.CallOption) (*PostRevision, error)
+	DiffRevisions(ctx context.Context, in *DiffRevisionsRequest, opts ...grpc.CallOption) (*DiffRevisionsResponse, error)
+	// RestoreRevision brings back the title and content of a revision and
+	// records the result as a new revision.
+	RestoreRevision(ctx context.Context, in *RestoreRevisionRequest, opts ...grpc.CallOption) (*Post, error)
+	// BatchGet returns up to 100 posts by id, missing posts are reported as
+	// NOT_FOUND items.
+	BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchPostsResponse, error)
+	// BatchCreate creates up to 100 posts in one transaction.
+	BatchCreate(ctx context.Context, in *BatchCreateRequest, opts ...grpc.CallOption) (*BatchPostsResponse, error)
+	// BatchUpdate updates up to 100 posts in one transaction.
+	BatchUpdate(ctx context.Context, in *BatchUpdateRequest, opts ...grpc.CallOption) (*BatchPostsResponse, error)
+	// BatchDelete moves up to 100 posts to the trash.
+	BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchPostsResponse, error)
+	// Import creates posts from a stream of records. Failed records are
+	// reported in the response, the other ones are imported anyway. The
+	// posts get new ids, created_at and published_at are reset to the time
+	// of the import rather than taken from the records.
+	Import(ctx context.Context, opts ...grpc.CallOption) (Posts_ImportClient, error)
+	// Export streams every post matching the filter, oldest first, with a
+	// progress message after every page of posts.
+	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (Posts_ExportClient, error)
+	// Watch streams post events as they happen. Browsers can use the
+	// server-sent events endpoint /posts/events instead.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Posts_WatchClient, error)
 }
 
 type postsClient struct {
@@ -55,6 +98,15 @@ func (c *postsClient) List(ctx context.Context, in *ListRequest, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *postsClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/posts.Posts/Search", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *postsClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Post, error) {
 	out := new(Post)
 	err := c.cc.Invoke(ctx, "/posts.Posts/Create", in, out, opts...)
@@ -82,15 +134,318 @@ func (c *postsClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grp
 	return out, nil
 }
 
+func (c *postsClient) Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*Post, error) {
+	out := new(Post)
+	err := c.cc.Invoke(ctx, "/posts.Posts/Submit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*Post, error) {
+	out := new(Post)
+	err := c.cc.Invoke(ctx, "/posts.Posts/Publish", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) Unpublish(ctx context.Context, in *UnpublishRequest, opts ...grpc.CallOption) (*Post, error) {
+	out := new(Post)
+	err := c.cc.Invoke(ctx, "/posts.Posts/Unpublish", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) Archive(ctx context.Context, in *ArchiveRequest, opts ...grpc.CallOption) (*Post, error) {
+	out := new(Post)
+	err := c.cc.Invoke(ctx, "/posts.Posts/Archive", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) Schedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*Post, error) {
+	out := new(Post)
+	err := c.cc.Invoke(ctx, "/posts.Posts/Schedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) Reschedule(ctx context.Context, in *RescheduleRequest, opts ...grpc.CallOption) (*Post, error) {
+	out := new(Post)
+	err := c.cc.Invoke(ctx, "/posts.Posts/Reschedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) CancelSchedule(ctx context.Context, in *CancelScheduleRequest, opts ...grpc.CallOption) (*Post, error) {
+	out := new(Post)
+	err := c.cc.Invoke(ctx, "/posts.Posts/CancelSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error) {
+	out := new(ListTrashResponse)
+	err := c.cc.Invoke(ctx, "/posts.Posts/ListTrash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*Post, error) {
+	out := new(Post)
+	err := c.cc.Invoke(ctx, "/posts.Posts/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) Purge(ctx context.Context, in *PurgeRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/posts.Posts/Purge", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) ListRevisions(ctx context.Context, in *ListRevisionsRequest, opts ...grpc.CallOption) (*ListRevisionsResponse, error) {
+	out := new(ListRevisionsResponse)
+	err := c.cc.Invoke(ctx, "/posts.Posts/ListRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) GetRevision(ctx context.Context, in *GetRevisionRequest, opts ...grpc.CallOption) (*PostRevision, error) {
+	out := new(PostRevision)
+	err := c.cc.Invoke(ctx, "/posts.Posts/GetRevision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) DiffRevisions(ctx context.Context, in *DiffRevisionsRequest, opts ...grpc.CallOption) (*DiffRevisionsResponse, error) {
+	out := new(DiffRevisionsResponse)
+	err := c.cc.Invoke(ctx, "/posts.Posts/DiffRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) RestoreRevision(ctx context.Context, in *RestoreRevisionRequest, opts ...grpc.CallOption) (*Post, error) {
+	out := new(Post)
+	err := c.cc.Invoke(ctx, "/posts.Posts/RestoreRevision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchPostsResponse, error) {
+	out := new(BatchPostsResponse)
+	err := c.cc.Invoke(ctx, "/posts.Posts/BatchGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) BatchCreate(ctx context.Context, in *BatchCreateRequest, opts ...grpc.CallOption) (*BatchPostsResponse, error) {
+	out := new(BatchPostsResponse)
+	err := c.cc.Invoke(ctx, "/posts.Posts/BatchCreate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) BatchUpdate(ctx context.Context, in *BatchUpdateRequest, opts ...grpc.CallOption) (*BatchPostsResponse, error) {
+	out := new(BatchPostsResponse)
+	err := c.cc.Invoke(ctx, "/posts.Posts/BatchUpdate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchPostsResponse, error) {
+	out := new(BatchPostsResponse)
+	err := c.cc.Invoke(ctx, "/posts.Posts/BatchDelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *postsClient) Import(ctx context.Context, opts ...grpc.CallOption) (Posts_ImportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Posts_ServiceDesc.Streams[0], "/posts.Posts/Import", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &postsImportClient{stream}
+	return x, nil
+}
+
+type Posts_ImportClient interface {
+	Send(*ImportRequest) error
+	CloseAndRecv() (*ImportResponse, error)
+	grpc.ClientStream
+}
+
+type postsImportClient struct {
+	grpc.ClientStream
+}
+
+func (x *postsImportClient) Send(m *ImportRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *postsImportClient) CloseAndRecv() (*ImportResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *postsClient) Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (Posts_ExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Posts_ServiceDesc.Streams[1], "/posts.Posts/Export", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &postsExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Posts_ExportClient interface {
+	Recv() (*ExportResponse, error)
+	grpc.ClientStream
+}
+
+type postsExportClient struct {
+	grpc.ClientStream
+}
+
+func (x *postsExportClient) Recv() (*ExportResponse, error) {
+	m := new(ExportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *postsClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Posts_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Posts_ServiceDesc.Streams[2], "/posts.Posts/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &postsWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Posts_WatchClient interface {
+	Recv() (*PostEvent, error)
+	grpc.ClientStream
+}
+
+type postsWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *postsWatchClient) Recv() (*PostEvent, error) {
+	m := new(PostEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // PostsServer is the server API for Posts service.
 // All implementations must embed UnimplementedPostsServer
 // for forward compatibility
 type PostsServer interface {
 	Get(context.Context, *GetRequest) (*GetResponse, error)
 	List(context.Context, *ListRequest) (*ListResponse, error)
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
 	Create(context.Context, *CreateRequest) (*Post, error)
 	Update(context.Context, *UpdateRequest) (*Post, error)
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// Submit sends a draft to review.
+	Submit(context.Context, *SubmitRequest) (*Post, error)
+	// Publish makes a draft or a reviewed post public.
+	Publish(context.Context, *PublishRequest) (*Post, error)
+	// Unpublish turns a published or archived post back into a draft.
+	Unpublish(context.Context, *UnpublishRequest) (*Post, error)
+	Archive(context.Context, *ArchiveRequest) (*Post, error)
+	// Schedule publishes a draft or a reviewed post at publish_at.
+	Schedule(context.Context, *ScheduleRequest) (*Post, error)
+	Reschedule(context.Context, *RescheduleRequest) (*Post, error)
+	CancelSchedule(context.Context, *CancelScheduleRequest) (*Post, error)
+	// ListTrash lists deleted posts, most recently deleted first.
+	ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error)
+	Restore(context.Context, *RestoreRequest) (*Post, error)
+	// Purge permanently removes a post from the trash.
+	Purge(context.Context, *PurgeRequest) (*DeleteResponse, error)
+	ListRevisions(context.Context, *ListRevisionsRequest) (*ListRevisionsResponse, error)
+	GetRevision(context.Context, *GetRevisionRequest) (*PostRevision, error)
+	DiffRevisions(context.Context, *DiffRevisionsRequest) (*DiffRevisionsResponse, error)
+	// RestoreRevision brings back the title and content of a revision and
+	// records the result as a new revision.
+	RestoreRevision(context.Context, *RestoreRevisionRequest) (*Post, error)
+	// BatchGet returns up to 100 posts by id, missing posts are reported as
+	// NOT_FOUND items.
+	BatchGet(context.Context, *BatchGetRequest) (*BatchPostsResponse, error)
+	// BatchCreate creates up to 100 posts in one transaction.
+	BatchCreate(context.Context, *BatchCreateRequest) (*BatchPostsResponse, error)
+	// BatchUpdate updates up to 100 posts in one transaction.
+	BatchUpdate(context.Context, *BatchUpdateRequest) (*BatchPostsResponse, error)
+	// BatchDelete moves up to 100 posts to the trash.
+	BatchDelete(context.Context, *BatchDeleteRequest) (*BatchPostsResponse, error)
+	// Import creates posts from a stream of records. Failed records are
+	// reported in the response, the other ones are imported anyway. The
+	// posts get new ids, created_at and published_at are reset to the time
+	// of the import rather than taken from the records.
+	Import(Posts_ImportServer) error
+	// Export streams every post matching the filter, oldest first, with a
+	// progress message after every page of posts.
+	Export(*ExportRequest, Posts_ExportServer) error
+	// Watch streams post events as they happen. Browsers can use the
+	// server-sent events endpoint /posts/events instead.
+	Watch(*WatchRequest, Posts_WatchServer) error
 	mustEmbedUnimplementedPostsServer()
 }
 
@@ -104,6 +459,9 @@ func (UnimplementedPostsServer) Get(context.Context, *GetRequest) (*GetResponse,
 func (UnimplementedPostsServer) List(context.Context, *ListRequest) (*ListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (UnimplementedPostsServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
 func (UnimplementedPostsServer) Create(context.Context, *CreateRequest) (*Post, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
 }
@@ -113,6 +471,69 @@ func (UnimplementedPostsServer) Update(context.Context, *UpdateRequest) (*Post,
 func (UnimplementedPostsServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedPostsServer) Submit(context.Context, *SubmitRequest) (*Post, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Submit not implemented")
+}
+func (UnimplementedPostsServer) Publish(context.Context, *PublishRequest) (*Post, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Publish not implemented")
+}
+func (UnimplementedPostsServer) Unpublish(context.Context, *UnpublishRequest) (*Post, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unpublish not implemented")
+}
+func (UnimplementedPostsServer) Archive(context.Context, *ArchiveRequest) (*Post, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Archive not implemented")
+}
+func (UnimplementedPostsServer) Schedule(context.Context, *ScheduleRequest) (*Post, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Schedule not implemented")
+}
+func (UnimplementedPostsServer) Reschedule(context.Context, *RescheduleRequest) (*Post, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reschedule not implemented")
+}
+func (UnimplementedPostsServer) CancelSchedule(context.Context, *CancelScheduleRequest) (*Post, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelSchedule not implemented")
+}
+func (UnimplementedPostsServer) ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (UnimplementedPostsServer) Restore(context.Context, *RestoreRequest) (*Post, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedPostsServer) Purge(context.Context, *PurgeRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Purge not implemented")
+}
+func (UnimplementedPostsServer) ListRevisions(context.Context, *ListRevisionsRequest) (*ListRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRevisions not implemented")
+}
+func (UnimplementedPostsServer) GetRevision(context.Context, *GetRevisionRequest) (*PostRevision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRevision not implemented")
+}
+func (UnimplementedPostsServer) DiffRevisions(context.Context, *DiffRevisionsRequest) (*DiffRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffRevisions not implemented")
+}
+func (UnimplementedPostsServer) RestoreRevision(context.Context, *RestoreRevisionRequest) (*Post, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreRevision not implemented")
+}
+func (UnimplementedPostsServer) BatchGet(context.Context, *BatchGetRequest) (*BatchPostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGet not implemented")
+}
+func (UnimplementedPostsServer) BatchCreate(context.Context, *BatchCreateRequest) (*BatchPostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreate not implemented")
+}
+func (UnimplementedPostsServer) BatchUpdate(context.Context, *BatchUpdateRequest) (*BatchPostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchUpdate not implemented")
+}
+func (UnimplementedPostsServer) BatchDelete(context.Context, *BatchDeleteRequest) (*BatchPostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDelete not implemented")
+}
+func (UnimplementedPostsServer) Import(Posts_ImportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Import not implemented")
+}
+func (UnimplementedPostsServer) Export(*ExportRequest, Posts_ExportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedPostsServer) Watch(*WatchRequest, Posts_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedPostsServer) mustEmbedUnimplementedPostsServer() {}
 
 // UnsafePostsServer may be embedded to opt out of forward compatibility for this service.
@@ -162,6 +583,24 @@ func _Posts_List_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Posts_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/Search",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Posts_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateRequest)
 	if err := dec(in); err != nil {
@@ -216,32 +655,1390 @@ func _Posts_Delete_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
-// Posts_ServiceDesc is the grpc.ServiceDesc for Posts service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var Posts_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "posts.Posts",
-	HandlerType: (*PostsServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Get",
-			Handler:    _Posts_Get_Handler,
-		},
-		{
-			MethodName: "List",
-			Handler:    _Posts_List_Handler,
-		},
-		{
-			MethodName: "Create",
-			Handler:    _Posts_Create_Handler,
-		},
-		{
-			MethodName: "Update",
-			Handler:    _Posts_Update_Handler,
-		},
-		{
-			MethodName: "Delete",
-			Handler:    _Posts_Delete_Handler,
+func _Posts_Submit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).Submit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/Submit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).Submit(ctx, req.(*SubmitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_Publish_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).Publish(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/Publish",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).Publish(ctx, req.(*PublishRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_Unpublish_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnpublishRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).Unpublish(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/Unpublish",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).Unpublish(ctx, req.(*UnpublishRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_Archive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).Archive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/Archive",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).Archive(ctx, req.(*ArchiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_Schedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).Schedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/Schedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).Schedule(ctx, req.(*ScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_Reschedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RescheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).Reschedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/Reschedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).Reschedule(ctx, req.(*RescheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_CancelSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).CancelSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/CancelSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).CancelSchedule(ctx, req.(*CancelScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).ListTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/ListTrash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).ListTrash(ctx, req.(*ListTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).Restore(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_Purge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).Purge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/Purge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).Purge(ctx, req.(*PurgeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_ListRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).ListRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/ListRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).ListRevisions(ctx, req.(*ListRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_GetRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).GetRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/GetRevision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).GetRevision(ctx, req.(*GetRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_DiffRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).DiffRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/DiffRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).DiffRevisions(ctx, req.(*DiffRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_RestoreRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).RestoreRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/RestoreRevision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).RestoreRevision(ctx, req.(*RestoreRevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_BatchGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).BatchGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/BatchGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).BatchGet(ctx, req.(*BatchGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_BatchCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).BatchCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/BatchCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).BatchCreate(ctx, req.(*BatchCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_BatchUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).BatchUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/BatchUpdate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).BatchUpdate(ctx, req.(*BatchUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_BatchDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PostsServer).BatchDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Posts/BatchDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PostsServer).BatchDelete(ctx, req.(*BatchDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Posts_Import_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PostsServer).Import(&postsImportServer{stream})
+}
+
+type Posts_ImportServer interface {
+	SendAndClose(*ImportResponse) error
+	Recv() (*ImportRequest, error)
+	grpc.ServerStream
+}
+
+type postsImportServer struct {
+	grpc.ServerStream
+}
+
+func (x *postsImportServer) SendAndClose(m *ImportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *postsImportServer) Recv() (*ImportRequest, error) {
+	m := new(ImportRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Posts_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PostsServer).Export(m, &postsExportServer{stream})
+}
+
+type Posts_ExportServer interface {
+	Send(*ExportResponse) error
+	grpc.ServerStream
+}
+
+type postsExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *postsExportServer) Send(m *ExportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Posts_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PostsServer).Watch(m, &postsWatchServer{stream})
+}
+
+type Posts_WatchServer interface {
+	Send(*PostEvent) error
+	grpc.ServerStream
+}
+
+type postsWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *postsWatchServer) Send(m *PostEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Posts_ServiceDesc is the grpc.ServiceDesc for Posts service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Posts_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "posts.Posts",
+	HandlerType: (*PostsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Get",
+			Handler:    _Posts_Get_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Posts_List_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _Posts_Search_Handler,
+		},
+		{
+			MethodName: "Create",
+			Handler:    _Posts_Create_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _Posts_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Posts_Delete_Handler,
+		},
+		{
+			MethodName: "Submit",
+			Handler:    _Posts_Submit_Handler,
+		},
+		{
+			MethodName: "Publish",
+			Handler:    _Posts_Publish_Handler,
+		},
+		{
+			MethodName: "Unpublish",
+			Handler:    _Posts_Unpublish_Handler,
+		},
+		{
+			MethodName: "Archive",
+			Handler:    _Posts_Archive_Handler,
+		},
+		{
+			MethodName: "Schedule",
+			Handler:    _Posts_Schedule_Handler,
+		},
+		{
+			MethodName: "Reschedule",
+			Handler:    _Posts_Reschedule_Handler,
+		},
+		{
+			MethodName: "CancelSchedule",
+			Handler:    _Posts_CancelSchedule_Handler,
+		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _Posts_ListTrash_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _Posts_Restore_Handler,
+		},
+		{
+			MethodName: "Purge",
+			Handler:    _Posts_Purge_Handler,
+		},
+		{
+			MethodName: "ListRevisions",
+			Handler:    _Posts_ListRevisions_Handler,
+		},
+		{
+			MethodName: "GetRevision",
+			Handler:    _Posts_GetRevision_Handler,
+		},
+		{
+			MethodName: "DiffRevisions",
+			Handler:    _Posts_DiffRevisions_Handler,
+		},
+		{
+			MethodName: "RestoreRevision",
+			Handler:    _Posts_RestoreRevision_Handler,
+		},
+		{
+			MethodName: "BatchGet",
+			Handler:    _Posts_BatchGet_Handler,
+		},
+		{
+			MethodName: "BatchCreate",
+			Handler:    _Posts_BatchCreate_Handler,
+		},
+		{
+			MethodName: "BatchUpdate",
+			Handler:    _Posts_BatchUpdate_Handler,
+		},
+		{
+			MethodName: "BatchDelete",
+			Handler:    _Posts_BatchDelete_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Import",
+			Handler:       _Posts_Import_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Export",
+			Handler:       _Posts_Export_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Posts_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "posts.proto",
+}
+
+// TaxonomyClient is the client API for Taxonomy service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TaxonomyClient interface {
+	GetCategory(ctx context.Context, in *GetCategoryRequest, opts ...grpc.CallOption) (*Category, error)
+	ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*ListCategoriesResponse, error)
+	CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*Category, error)
+	UpdateCategory(ctx context.Context, in *UpdateCategoryRequest, opts ...grpc.CallOption) (*Category, error)
+	DeleteCategory(ctx context.Context, in *DeleteCategoryRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	GetTag(ctx context.Context, in *GetTagRequest, opts ...grpc.CallOption) (*Tag, error)
+	ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error)
+	CreateTag(ctx context.Context, in *CreateTagRequest, opts ...grpc.CallOption) (*Tag, error)
+	UpdateTag(ctx context.Context, in *UpdateTagRequest, opts ...grpc.CallOption) (*Tag, error)
+	DeleteTag(ctx context.Context, in *DeleteTagRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+}
+
+type taxonomyClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTaxonomyClient(cc grpc.ClientConnInterface) TaxonomyClient {
+	return &taxonomyClient{cc}
+}
+
+func (c *taxonomyClient) GetCategory(ctx context.Context, in *GetCategoryRequest, opts ...grpc.CallOption) (*Category, error) {
+	out := new(Category)
+	err := c.cc.Invoke(ctx, "/posts.Taxonomy/GetCategory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taxonomyClient) ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*ListCategoriesResponse, error) {
+	out := new(ListCategoriesResponse)
+	err := c.cc.Invoke(ctx, "/posts.Taxonomy/ListCategories", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taxonomyClient) CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*Category, error) {
+	out := new(Category)
+	err := c.cc.Invoke(ctx, "/posts.Taxonomy/CreateCategory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taxonomyClient) UpdateCategory(ctx context.Context, in *UpdateCategoryRequest, opts ...grpc.CallOption) (*Category, error) {
+	out := new(Category)
+	err := c.cc.Invoke(ctx, "/posts.Taxonomy/UpdateCategory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taxonomyClient) DeleteCategory(ctx context.Context, in *DeleteCategoryRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/posts.Taxonomy/DeleteCategory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taxonomyClient) GetTag(ctx context.Context, in *GetTagRequest, opts ...grpc.CallOption) (*Tag, error) {
+	out := new(Tag)
+	err := c.cc.Invoke(ctx, "/posts.Taxonomy/GetTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taxonomyClient) ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error) {
+	out := new(ListTagsResponse)
+	err := c.cc.Invoke(ctx, "/posts.Taxonomy/ListTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taxonomyClient) CreateTag(ctx context.Context, in *CreateTagRequest, opts ...grpc.CallOption) (*Tag, error) {
+	out := new(Tag)
+	err := c.cc.Invoke(ctx, "/posts.Taxonomy/CreateTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taxonomyClient) UpdateTag(ctx context.Context, in *UpdateTagRequest, opts ...grpc.CallOption) (*Tag, error) {
+	out := new(Tag)
+	err := c.cc.Invoke(ctx, "/posts.Taxonomy/UpdateTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taxonomyClient) DeleteTag(ctx context.Context, in *DeleteTagRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/posts.Taxonomy/DeleteTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TaxonomyServer is the server API for Taxonomy service.
+// All implementations must embed UnimplementedTaxonomyServer
+// for forward compatibility
+type TaxonomyServer interface {
+	GetCategory(context.Context, *GetCategoryRequest) (*Category, error)
+	ListCategories(context.Context, *ListCategoriesRequest) (*ListCategoriesResponse, error)
+	CreateCategory(context.Context, *CreateCategoryRequest) (*Category, error)
+	UpdateCategory(context.Context, *UpdateCategoryRequest) (*Category, error)
+	DeleteCategory(context.Context, *DeleteCategoryRequest) (*DeleteResponse, error)
+	GetTag(context.Context, *GetTagRequest) (*Tag, error)
+	ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error)
+	CreateTag(context.Context, *CreateTagRequest) (*Tag, error)
+	UpdateTag(context.Context, *UpdateTagRequest) (*Tag, error)
+	DeleteTag(context.Context, *DeleteTagRequest) (*DeleteResponse, error)
+	mustEmbedUnimplementedTaxonomyServer()
+}
+
+// UnimplementedTaxonomyServer must be embedded to have forward compatible implementations.
+type UnimplementedTaxonomyServer struct {
+}
+
+func (UnimplementedTaxonomyServer) GetCategory(context.Context, *GetCategoryRequest) (*Category, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCategory not implemented")
+}
+func (UnimplementedTaxonomyServer) ListCategories(context.Context, *ListCategoriesRequest) (*ListCategoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCategories not implemented")
+}
+func (UnimplementedTaxonomyServer) CreateCategory(context.Context, *CreateCategoryRequest) (*Category, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCategory not implemented")
+}
+func (UnimplementedTaxonomyServer) UpdateCategory(context.Context, *UpdateCategoryRequest) (*Category, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCategory not implemented")
+}
+func (UnimplementedTaxonomyServer) DeleteCategory(context.Context, *DeleteCategoryRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCategory not implemented")
+}
+func (UnimplementedTaxonomyServer) GetTag(context.Context, *GetTagRequest) (*Tag, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTag not implemented")
+}
+func (UnimplementedTaxonomyServer) ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTags not implemented")
+}
+func (UnimplementedTaxonomyServer) CreateTag(context.Context, *CreateTagRequest) (*Tag, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTag not implemented")
+}
+func (UnimplementedTaxonomyServer) UpdateTag(context.Context, *UpdateTagRequest) (*Tag, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTag not implemented")
+}
+func (UnimplementedTaxonomyServer) DeleteTag(context.Context, *DeleteTagRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTag not implemented")
+}
+func (UnimplementedTaxonomyServer) mustEmbedUnimplementedTaxonomyServer() {}
+
+// UnsafeTaxonomyServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TaxonomyServer will
+// result in compilation errors.
+type UnsafeTaxonomyServer interface {
+	mustEmbedUnimplementedTaxonomyServer()
+}
+
+func RegisterTaxonomyServer(s grpc.ServiceRegistrar, srv TaxonomyServer) {
+	s.RegisterService(&Taxonomy_ServiceDesc, srv)
+}
+
+func _Taxonomy_GetCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaxonomyServer).GetCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Taxonomy/GetCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaxonomyServer).GetCategory(ctx, req.(*GetCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taxonomy_ListCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaxonomyServer).ListCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Taxonomy/ListCategories",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaxonomyServer).ListCategories(ctx, req.(*ListCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taxonomy_CreateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaxonomyServer).CreateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Taxonomy/CreateCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaxonomyServer).CreateCategory(ctx, req.(*CreateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taxonomy_UpdateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaxonomyServer).UpdateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Taxonomy/UpdateCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaxonomyServer).UpdateCategory(ctx, req.(*UpdateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taxonomy_DeleteCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaxonomyServer).DeleteCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Taxonomy/DeleteCategory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaxonomyServer).DeleteCategory(ctx, req.(*DeleteCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taxonomy_GetTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaxonomyServer).GetTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Taxonomy/GetTag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaxonomyServer).GetTag(ctx, req.(*GetTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taxonomy_ListTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaxonomyServer).ListTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Taxonomy/ListTags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaxonomyServer).ListTags(ctx, req.(*ListTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taxonomy_CreateTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaxonomyServer).CreateTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Taxonomy/CreateTag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaxonomyServer).CreateTag(ctx, req.(*CreateTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taxonomy_UpdateTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaxonomyServer).UpdateTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Taxonomy/UpdateTag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaxonomyServer).UpdateTag(ctx, req.(*UpdateTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Taxonomy_DeleteTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaxonomyServer).DeleteTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Taxonomy/DeleteTag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaxonomyServer).DeleteTag(ctx, req.(*DeleteTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Taxonomy_ServiceDesc is the grpc.ServiceDesc for Taxonomy service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Taxonomy_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "posts.Taxonomy",
+	HandlerType: (*TaxonomyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCategory",
+			Handler:    _Taxonomy_GetCategory_Handler,
+		},
+		{
+			MethodName: "ListCategories",
+			Handler:    _Taxonomy_ListCategories_Handler,
+		},
+		{
+			MethodName: "CreateCategory",
+			Handler:    _Taxonomy_CreateCategory_Handler,
+		},
+		{
+			MethodName: "UpdateCategory",
+			Handler:    _Taxonomy_UpdateCategory_Handler,
+		},
+		{
+			MethodName: "DeleteCategory",
+			Handler:    _Taxonomy_DeleteCategory_Handler,
+		},
+		{
+			MethodName: "GetTag",
+			Handler:    _Taxonomy_GetTag_Handler,
+		},
+		{
+			MethodName: "ListTags",
+			Handler:    _Taxonomy_ListTags_Handler,
+		},
+		{
+			MethodName: "CreateTag",
+			Handler:    _Taxonomy_CreateTag_Handler,
+		},
+		{
+			MethodName: "UpdateTag",
+			Handler:    _Taxonomy_UpdateTag_Handler,
+		},
+		{
+			MethodName: "DeleteTag",
+			Handler:    _Taxonomy_DeleteTag_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "posts.proto",
+}
+
+// AuthorsClient is the client API for Authors service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AuthorsClient interface {
+	GetAuthor(ctx context.Context, in *GetAuthorRequest, opts ...grpc.CallOption) (*Author, error)
+	ListAuthors(ctx context.Context, in *ListAuthorsRequest, opts ...grpc.CallOption) (*ListAuthorsResponse, error)
+	CreateAuthor(ctx context.Context, in *CreateAuthorRequest, opts ...grpc.CallOption) (*Author, error)
+	UpdateAuthor(ctx context.Context, in *UpdateAuthorRequest, opts ...grpc.CallOption) (*Author, error)
+	DeleteAuthor(ctx context.Context, in *DeleteAuthorRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+}
+
+type authorsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuthorsClient(cc grpc.ClientConnInterface) AuthorsClient {
+	return &authorsClient{cc}
+}
+
+func (c *authorsClient) GetAuthor(ctx context.Context, in *GetAuthorRequest, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, "/posts.Authors/GetAuthor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorsClient) ListAuthors(ctx context.Context, in *ListAuthorsRequest, opts ...grpc.CallOption) (*ListAuthorsResponse, error) {
+	out := new(ListAuthorsResponse)
+	err := c.cc.Invoke(ctx, "/posts.Authors/ListAuthors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorsClient) CreateAuthor(ctx context.Context, in *CreateAuthorRequest, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, "/posts.Authors/CreateAuthor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorsClient) UpdateAuthor(ctx context.Context, in *UpdateAuthorRequest, opts ...grpc.CallOption) (*Author, error) {
+	out := new(Author)
+	err := c.cc.Invoke(ctx, "/posts.Authors/UpdateAuthor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authorsClient) DeleteAuthor(ctx context.Context, in *DeleteAuthorRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/posts.Authors/DeleteAuthor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthorsServer is the server API for Authors service.
+// All implementations must embed UnimplementedAuthorsServer
+// for forward compatibility
+type AuthorsServer interface {
+	GetAuthor(context.Context, *GetAuthorRequest) (*Author, error)
+	ListAuthors(context.Context, *ListAuthorsRequest) (*ListAuthorsResponse, error)
+	CreateAuthor(context.Context, *CreateAuthorRequest) (*Author, error)
+	UpdateAuthor(context.Context, *UpdateAuthorRequest) (*Author, error)
+	DeleteAuthor(context.Context, *DeleteAuthorRequest) (*DeleteResponse, error)
+	mustEmbedUnimplementedAuthorsServer()
+}
+
+// UnimplementedAuthorsServer must be embedded to have forward compatible implementations.
+type UnimplementedAuthorsServer struct {
+}
+
+func (UnimplementedAuthorsServer) GetAuthor(context.Context, *GetAuthorRequest) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuthor not implemented")
+}
+func (UnimplementedAuthorsServer) ListAuthors(context.Context, *ListAuthorsRequest) (*ListAuthorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuthors not implemented")
+}
+func (UnimplementedAuthorsServer) CreateAuthor(context.Context, *CreateAuthorRequest) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAuthor not implemented")
+}
+func (UnimplementedAuthorsServer) UpdateAuthor(context.Context, *UpdateAuthorRequest) (*Author, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAuthor not implemented")
+}
+func (UnimplementedAuthorsServer) DeleteAuthor(context.Context, *DeleteAuthorRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAuthor not implemented")
+}
+func (UnimplementedAuthorsServer) mustEmbedUnimplementedAuthorsServer() {}
+
+// UnsafeAuthorsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AuthorsServer will
+// result in compilation errors.
+type UnsafeAuthorsServer interface {
+	mustEmbedUnimplementedAuthorsServer()
+}
+
+func RegisterAuthorsServer(s grpc.ServiceRegistrar, srv AuthorsServer) {
+	s.RegisterService(&Authors_ServiceDesc, srv)
+}
+
+func _Authors_GetAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorsServer).GetAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Authors/GetAuthor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorsServer).GetAuthor(ctx, req.(*GetAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authors_ListAuthors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuthorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorsServer).ListAuthors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Authors/ListAuthors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorsServer).ListAuthors(ctx, req.(*ListAuthorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authors_CreateAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorsServer).CreateAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Authors/CreateAuthor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorsServer).CreateAuthor(ctx, req.(*CreateAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authors_UpdateAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorsServer).UpdateAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Authors/UpdateAuthor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorsServer).UpdateAuthor(ctx, req.(*UpdateAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authors_DeleteAuthor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAuthorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthorsServer).DeleteAuthor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Authors/DeleteAuthor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthorsServer).DeleteAuthor(ctx, req.(*DeleteAuthorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Authors_ServiceDesc is the grpc.ServiceDesc for Authors service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Authors_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "posts.Authors",
+	HandlerType: (*AuthorsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAuthor",
+			Handler:    _Authors_GetAuthor_Handler,
+		},
+		{
+			MethodName: "ListAuthors",
+			Handler:    _Authors_ListAuthors_Handler,
+		},
+		{
+			MethodName: "CreateAuthor",
+			Handler:    _Authors_CreateAuthor_Handler,
+		},
+		{
+			MethodName: "UpdateAuthor",
+			Handler:    _Authors_UpdateAuthor_Handler,
+		},
+		{
+			MethodName: "DeleteAuthor",
+			Handler:    _Authors_DeleteAuthor_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "posts.proto",
+}
+
+// WebhooksClient is the client API for Webhooks service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WebhooksClient interface {
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*WebhookSubscription, error)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// TestWebhook sends a webhook.test delivery right away and returns its
+	// outcome.
+	TestWebhook(ctx context.Context, in *TestWebhookRequest, opts ...grpc.CallOption) (*WebhookDelivery, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+}
+
+type webhooksClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWebhooksClient(cc grpc.ClientConnInterface) WebhooksClient {
+	return &webhooksClient{cc}
+}
+
+func (c *webhooksClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*WebhookSubscription, error) {
+	out := new(WebhookSubscription)
+	err := c.cc.Invoke(ctx, "/posts.Webhooks/CreateWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, "/posts.Webhooks/ListWebhooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/posts.Webhooks/DeleteWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksClient) TestWebhook(ctx context.Context, in *TestWebhookRequest, opts ...grpc.CallOption) (*WebhookDelivery, error) {
+	out := new(WebhookDelivery)
+	err := c.cc.Invoke(ctx, "/posts.Webhooks/TestWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, "/posts.Webhooks/ListWebhookDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WebhooksServer is the server API for Webhooks service.
+// All implementations must embed UnimplementedWebhooksServer
+// for forward compatibility
+type WebhooksServer interface {
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*WebhookSubscription, error)
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteResponse, error)
+	// TestWebhook sends a webhook.test delivery right away and returns its
+	// outcome.
+	TestWebhook(context.Context, *TestWebhookRequest) (*WebhookDelivery, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	mustEmbedUnimplementedWebhooksServer()
+}
+
+// UnimplementedWebhooksServer must be embedded to have forward compatible implementations.
+type UnimplementedWebhooksServer struct {
+}
+
+func (UnimplementedWebhooksServer) CreateWebhook(context.Context, *CreateWebhookRequest) (*WebhookSubscription, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedWebhooksServer) ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedWebhooksServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedWebhooksServer) TestWebhook(context.Context, *TestWebhookRequest) (*WebhookDelivery, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TestWebhook not implemented")
+}
+func (UnimplementedWebhooksServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedWebhooksServer) mustEmbedUnimplementedWebhooksServer() {}
+
+// UnsafeWebhooksServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WebhooksServer will
+// result in compilation errors.
+type UnsafeWebhooksServer interface {
+	mustEmbedUnimplementedWebhooksServer()
+}
+
+func RegisterWebhooksServer(s grpc.ServiceRegistrar, srv WebhooksServer) {
+	s.RegisterService(&Webhooks_ServiceDesc, srv)
+}
+
+func _Webhooks_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhooksServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Webhooks/CreateWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhooksServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Webhooks_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhooksServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Webhooks/ListWebhooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhooksServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Webhooks_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhooksServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Webhooks/DeleteWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhooksServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Webhooks_TestWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TestWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhooksServer).TestWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Webhooks/TestWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhooksServer).TestWebhook(ctx, req.(*TestWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Webhooks_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhooksServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/posts.Webhooks/ListWebhookDeliveries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhooksServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Webhooks_ServiceDesc is the grpc.ServiceDesc for Webhooks service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Webhooks_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "posts.Webhooks",
+	HandlerType: (*WebhooksServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _Webhooks_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _Webhooks_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _Webhooks_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "TestWebhook",
+			Handler:    _Webhooks_TestWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _Webhooks_ListWebhookDeliveries_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -2,17 +2,31 @@ package main
 
 import (
 	"context"
-	"log"
+	"io"
 	"os"
 	"os/signal"
-	"sync"
 	"syscall"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/internal/cache"
+	"github.com/kokhno-nikolay/news/internal/health"
+	"github.com/kokhno-nikolay/news/internal/lifecycle"
+	"github.com/kokhno-nikolay/news/internal/logging"
+	"github.com/kokhno-nikolay/news/internal/metrics"
+	"github.com/kokhno-nikolay/news/internal/migrate"
+	"github.com/kokhno-nikolay/news/internal/outbox"
 	"github.com/kokhno-nikolay/news/internal/repository"
+	"github.com/kokhno-nikolay/news/internal/repository/cached"
+	"github.com/kokhno-nikolay/news/internal/repository/instrumented"
 	"github.com/kokhno-nikolay/news/internal/repository/postgresql"
+	"github.com/kokhno-nikolay/news/internal/scheduler"
 	"github.com/kokhno-nikolay/news/internal/server"
 	"github.com/kokhno-nikolay/news/internal/service"
+	"github.com/kokhno-nikolay/news/internal/tracing"
+	"github.com/kokhno-nikolay/news/internal/webhooks"
+	"github.com/kokhno-nikolay/news/migrations"
 )
 
 const (
@@ -28,41 +42,120 @@ const (
 // @host            localhost:8000
 // @BasePath        /
 func main() {
-	ctx := context.Background()
+	// cancelled on SIGTERM or SIGINT, which starts the shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
 	cfg := config.GetConfig()
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
+	if err := logging.Init(cfg); err != nil {
+		panic(err.Error())
+	}
+
+	shutdownTracing, err := tracing.Init(ctx, cfg)
+	if err != nil {
+		panic(err.Error())
+	}
 
 	db, err := postgresql.NewClient(dns)
 	if err != nil {
 		panic(err.Error())
 	}
 
-	repos := repository.NewRepository(db)
-	services := service.NewService(repos)
-	server := server.NewServer(services.PostService)
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		panic(err.Error())
+	}
 
-	wg := &sync.WaitGroup{}
-	wg.Add(2)
+	// `main migrate ...` manages the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrate(ctx, migrator, os.Args[2:])
+		db.Close()
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		return
+	}
 
-	// starting grpc server
-	go func() {
-		defer wg.Done()
+	// replicas started together wait for the one holding the migration lock
+	if cfg.AutoMigrate {
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			panic(err.Error())
+		}
 
-		if err := server.StartGrpcServer(cfg); err != nil {
-			log.Fatal(err)
+		for _, migration := range applied {
+			logrus.WithFields(logrus.Fields{
+				"version": migration.Version,
+				"name":    migration.Name,
+			}).Info("migration applied")
 		}
-	}()
+	}
 
-	// starting http server
-	go func() {
-		defer wg.Done()
+	metrics.RegisterDB(db.DB, "boosters")
 
-		if err := server.StartHttpServer(ctx, cfg); err != nil {
-			log.Fatal(err)
-		}
-	}()
+	// wakes up Watch streams whenever the posts trigger records an event
+	listener := postgresql.NewPostListener(dns)
+
+	// timing and tracing the post queries that reach the database
+	repos := repository.NewRepository(db, listener)
+	repos.Posts = instrumented.NewPostRepo(repos.Posts)
 
-	wg.Wait()
+	// caching post reads, wrapped before anything writes posts so every
+	// change drops what it makes stale
+	postCache, err := cache.New(cfg)
+	if err != nil {
+		panic(err.Error())
+	}
+	if postCache != nil {
+		repos.Posts = cached.NewPostRepo(repos.Posts, postCache, cfg.CacheTTL)
+	}
+
+	services := service.NewService(repos, cfg)
+	server := server.NewServer(services, health.NewChecker(db))
+
+	// sending webhook deliveries, replicas claim disjoint deliveries
+	pool := webhooks.NewPool(repos.Webhooks, webhooks.NewSender(cfg.WebhookTimeout), cfg)
+
+	// delivering post events to webhook subscriptions and other services,
+	// replicas claim disjoint events
+	var publisher outbox.Publisher = webhooks.NewDispatcher(repos.Webhooks, pool)
+
+	configured, err := outbox.NewPublisher(cfg)
+	if err != nil {
+		panic(err.Error())
+	}
+	if configured != nil {
+		publisher = outbox.MultiPublisher{publisher, configured}
+	}
+
+	app := lifecycle.New(cfg.ShutdownTimeout, cfg.ShutdownDrainDelay)
+
+	// health checks report the service as going away before anything stops
+	app.OnDrain(server.Drain)
+
+	// the gateway goes first, it needs the gRPC server for the requests it
+	// still has in flight. Its connections outlive ctx for the same reason.
+	app.AddServer("http", func() error { return server.StartHttpServer(context.Background(), cfg) }, server.ShutdownHttpServer)
+	app.AddServer("grpc", func() error { return server.StartGrpcServer(cfg) }, server.ShutdownGrpcServer)
+
+	app.AddWorker("post listener", listener.Run)
+
+	// publishing scheduled posts and emptying the trash, safe to run on every replica
+	app.AddWorker("scheduler", scheduler.NewScheduler(repos.Posts, cfg).Run)
+	app.AddWorker("retention", scheduler.NewRetention(repos.Posts, repos.Webhooks, cfg).Run)
+
+	app.AddWorker("webhooks", pool.Run)
+	app.AddWorker("outbox", outbox.NewRelay(repos.Outbox, repos.Notifier, publisher, cfg).Run)
+
+	// spans recorded during the shutdown are flushed before the pool goes
+	app.AddCloser("tracing", func() error { return shutdownTracing(context.Background()) })
+	if closer, ok := postCache.(io.Closer); ok {
+		app.AddCloser("cache", closer.Close)
+	}
+	app.AddCloser("database", db.Close)
+
+	if err := app.Run(ctx); err != nil {
+		logrus.Fatal(err)
+	}
 }
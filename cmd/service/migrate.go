@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/kokhno-nikolay/news/internal/migrate"
+)
+
+const migrateUsage = `usage: main migrate <command>
+
+commands:
+  up         apply all pending migrations
+  down N     revert the last N migrations
+  status     list migrations and the current version
+  force V    set the version to V without running migrations, clearing the dirty flag`
+
+// runMigrate serves the migrate subcommand of the service binary.
+func runMigrate(ctx context.Context, migrator *migrate.Migrator, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		printMigrations("applied", applied)
+		return err
+	case "down":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("down: N must be a positive number, got %q", args[1])
+		}
+
+		reverted, err := migrator.Down(ctx, n)
+		printMigrations("reverted", reverted)
+		return err
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		printStatus(status)
+		return nil
+	case "force":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("force: V must be a version or zero, got %q", args[1])
+		}
+
+		if err := migrator.Force(ctx, version); err != nil {
+			return err
+		}
+
+		fmt.Printf("version forced to %d\n", version)
+		return nil
+	}
+
+	return fmt.Errorf("unknown migrate command %q\n\n%s", args[0], migrateUsage)
+}
+
+func printMigrations(verb string, migrations []migrate.Migration) {
+	if len(migrations) == 0 {
+		fmt.Printf("nothing %s\n", verb)
+		return
+	}
+
+	for _, migration := range migrations {
+		fmt.Printf("%s %04d_%s\n", verb, migration.Version, migration.Name)
+	}
+}
+
+func printStatus(status *migrate.Status) {
+	fmt.Printf("version %d, dirty %t\n\n", status.Version, status.Dirty)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, migration := range status.Migrations {
+		fmt.Fprintf(w, "%04d\t%s\t%t\n", migration.Version, migration.Name, migration.Applied)
+	}
+	w.Flush()
+}
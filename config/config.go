@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/caarlos0/env"
 	"github.com/joho/godotenv"
@@ -17,10 +18,126 @@ type Config struct {
 	GrpcAddress string `env:"SERVER_PORT" envDefault:"localhost:50051"`
 	HttpAddress string `env:"HTTP_ADDRESS" envDefault:"localhost:8000"`
 	PostresDNS  string `env:"POSTGRES_DNS"`
+
+	// SearchLanguage is the PostgreSQL text search configuration used to
+	// parse search queries and to index new posts. Posts created before a
+	// change keep their posts.search_language until it is updated.
+	SearchLanguage string `env:"SEARCH_LANGUAGE" envDefault:"english"`
+
+	// JWTAlgorithm is either HS256, verified with JWTSecret, or RS256,
+	// verified with the PEM encoded public key at JWTPublicKeyPath.
+	JWTAlgorithm     string `env:"JWT_ALGORITHM" envDefault:"HS256"`
+	JWTSecret        string `env:"JWT_SECRET"`
+	JWTPublicKeyPath string `env:"JWT_PUBLIC_KEY_PATH"`
+	JWTIssuer        string `env:"JWT_ISSUER"`
+	JWTAudience      string `env:"JWT_AUDIENCE"`
+
+	// SchedulerInterval is how often due scheduled posts are published,
+	// SchedulerBatchSize caps how many are published per query, at most 200.
+	SchedulerInterval  time.Duration `env:"SCHEDULER_INTERVAL" envDefault:"30s"`
+	SchedulerBatchSize int           `env:"SCHEDULER_BATCH_SIZE" envDefault:"100"`
+
+	// TrashRetentionDays is how long deleted posts stay in the trash before
+	// they are purged, zero keeps them forever. TrashPurgeInterval is how
+	// often the trash is checked.
+	TrashRetentionDays int           `env:"TRASH_RETENTION_DAYS" envDefault:"30"`
+	TrashPurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL" envDefault:"1h"`
+
+	// PostEventsRetention is how long the events of the Watch feed and the
+	// outbox are kept, delivered or not, zero keeps them forever. They are
+	// purged along with the trash.
+	PostEventsRetention time.Duration `env:"POST_EVENTS_RETENTION" envDefault:"168h"`
+
+	// OutboxPublisher is where the outbox relay delivers post events: stdout,
+	// file (appended to OutboxFile) or webhook (posted to OutboxWebhookURL).
+	// Events are relayed to webhook subscriptions either way, empty relays
+	// them there alone.
+	OutboxPublisher  string `env:"OUTBOX_PUBLISHER"`
+	OutboxFile       string `env:"OUTBOX_FILE" envDefault:"post_events.jsonl"`
+	OutboxWebhookURL string `env:"OUTBOX_WEBHOOK_URL"`
+
+	// OutboxInterval is how often events due to be retried are looked for,
+	// new events are relayed as soon as they are recorded. A failing event is
+	// retried with a growing delay up to OutboxMaxAttempts times.
+	// OutboxBatchSize is at most 200.
+	OutboxInterval    time.Duration `env:"OUTBOX_INTERVAL" envDefault:"10s"`
+	OutboxBatchSize   int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+	OutboxMaxAttempts int           `env:"OUTBOX_MAX_ATTEMPTS" envDefault:"10"`
+
+	// WebhookWorkers is the number of webhook deliveries sent at once, each
+	// waiting at most WebhookTimeout for a response. WebhookInterval is how
+	// often deliveries due to be retried are looked for, a failing delivery
+	// is retried with a growing delay up to WebhookMaxAttempts times.
+	// WebhookBatchSize is at most 200.
+	WebhookWorkers     int           `env:"WEBHOOK_WORKERS" envDefault:"4"`
+	WebhookTimeout     time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	WebhookInterval    time.Duration `env:"WEBHOOK_INTERVAL" envDefault:"5s"`
+	WebhookBatchSize   int           `env:"WEBHOOK_BATCH_SIZE" envDefault:"100"`
+	WebhookMaxAttempts int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"10"`
+
+	// WebhookDeliveriesRetention is how long the delivery log is kept, zero
+	// keeps it forever. Pending deliveries are never purged.
+	WebhookDeliveriesRetention time.Duration `env:"WEBHOOK_DELIVERIES_RETENTION" envDefault:"720h"`
+
+	// CacheBackend caches post reads in memory (an LRU of CacheSize posts
+	// and pages per replica) or in Redis at RedisAddress, shared by all
+	// replicas. Empty disables the cache. Cached reads are kept for CacheTTL
+	// at most, changes made through the service drop them earlier.
+	//
+	// The memory backend is meant for a single replica: a change only drops
+	// the posts cached by the replica that made it, the others keep serving
+	// the old post for up to CacheTTL. Run several replicas with redis.
+	CacheBackend  string        `env:"CACHE_BACKEND"`
+	CacheSize     int           `env:"CACHE_SIZE" envDefault:"10000"`
+	CacheTTL      time.Duration `env:"CACHE_TTL" envDefault:"30s"`
+	RedisAddress  string        `env:"REDIS_ADDRESS" envDefault:"localhost:6379"`
+	RedisPassword string        `env:"REDIS_PASSWORD"`
+	RedisDB       int           `env:"REDIS_DB"`
+
+	// TracingExporter is where OpenTelemetry spans go: otlp (over gRPC to
+	// TracingOTLPEndpoint), stdout or none. W3C trace context of incoming
+	// requests is passed on either way. TracingSampleRatio is the share of
+	// new traces that are recorded, traces started upstream follow the
+	// decision of the caller.
+	TracingExporter     string  `env:"TRACING_EXPORTER" envDefault:"none"`
+	TracingOTLPEndpoint string  `env:"TRACING_OTLP_ENDPOINT" envDefault:"localhost:4317"`
+	TracingOTLPInsecure bool    `env:"TRACING_OTLP_INSECURE" envDefault:"true"`
+	TracingSampleRatio  float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+
+	// AutoMigrate applies pending migrations on startup, replicas started
+	// together take turns. Otherwise run the migrate subcommand first.
+	AutoMigrate bool `env:"AUTO_MIGRATE"`
+
+	// ShutdownDrainDelay is how long the service keeps taking requests after
+	// it starts failing readiness checks on SIGTERM, so load balancers stop
+	// sending it traffic first. In-flight requests and workers are then given
+	// ShutdownTimeout to finish before they are cut off.
+	ShutdownDrainDelay time.Duration `env:"SHUTDOWN_DRAIN_DELAY" envDefault:"5s"`
+	ShutdownTimeout    time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"30s"`
+
+	// LogFormat is json, one object per line, or text for reading logs in a
+	// terminal. LogLevel is the least severe level logged, access logs are
+	// written at info.
+	LogFormat string `env:"LOG_FORMAT" envDefault:"json"`
+	LogLevel  string `env:"LOG_LEVEL" envDefault:"info"`
+
+	// RBACPolicyPath points to the YAML file mapping RPCs to allowed roles.
+	RBACPolicyPath string `env:"RBAC_POLICY_PATH" envDefault:"config/policy.yaml"`
+
+	// PublicMethods are full gRPC method names callable without a token.
+	PublicMethods []string `env:"AUTH_PUBLIC_METHODS" envSeparator:"," envDefault:"/posts.Posts/Get,/posts.Posts/List,/posts.Posts/Search,/posts.Posts/BatchGet,/posts.Posts/Watch,/posts.Taxonomy/GetCategory,/posts.Taxonomy/ListCategories,/posts.Taxonomy/GetTag,/posts.Taxonomy/ListTags,/posts.Authors/GetAuthor,/posts.Authors/ListAuthors,/grpc.health.v1.Health/Check,/grpc.health.v1.Health/Watch,/grpc.reflection.v1.ServerReflection/ServerReflectionInfo,/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"`
 }
 
 func (c *Config) String() string {
-	b, _ := json.MarshalIndent(c, "", "    ")
+	safe := *c
+	if safe.JWTSecret != "" {
+		safe.JWTSecret = "***"
+	}
+	if safe.RedisPassword != "" {
+		safe.RedisPassword = "***"
+	}
+
+	b, _ := json.MarshalIndent(safe, "", "    ")
 	return string(b)
 }
 
@@ -0,0 +1,40 @@
+package domain
+
+import "fmt"
+
+// MaxBatchSize is the number of items a batch request may carry.
+const MaxBatchSize = 100
+
+// PostBatchUpdate is one item of a batch update.
+type PostBatchUpdate struct {
+	ID    int       `json:"id"`
+	Input PostInput `json:"input"`
+}
+
+// PostBatchResult is the outcome of the batch item at Index, either Post or
+// Err is set.
+type PostBatchResult struct {
+	Index int   `json:"index"`
+	Post  *Post `json:"post,omitempty"`
+	Err   error `json:"-"`
+}
+
+// BatchItemError reports which item of a batch made it fail.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// PostImport is a record of an import, the post is created in Status.
+type PostImport struct {
+	Input  PostInput  `json:"input"`
+	Status PostStatus `json:"status"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type PostEventType string
+
+const (
+	PostCreated PostEventType = "created"
+	PostUpdated PostEventType = "updated"
+	PostDeleted PostEventType = "deleted"
+)
+
+// PostEvent is a change of a post recorded for the Watch feed and the
+// outbox. Published tells whether the post was public before or after the
+// change. Post is the current state of the post, it is only set on created
+// and updated events of the feed. Payload is the post as it was right after
+// the change, it is only read by the outbox relay along with the Status and
+// PreviousStatus of the post and the number of delivery Attempts.
+type PostEvent struct {
+	ID        int64           `json:"id"`
+	Type      PostEventType   `json:"type"`
+	PostID    int             `json:"post_id"`
+	AuthorID  int             `json:"author_id,omitempty"`
+	Published bool            `json:"published"`
+	CreatedAt time.Time       `json:"created_at"`
+	Post      *Post           `json:"post,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+
+	Status         PostStatus `json:"status,omitempty"`
+	PreviousStatus PostStatus `json:"previous_status,omitempty"`
+	Attempts       int        `json:"-"`
+}
@@ -3,16 +3,84 @@ package domain
 import "time"
 
 type Post struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          int         `json:"id"`
+	Title       string      `json:"title"`
+	Content     string      `json:"content"`
+	AuthorID    int         `json:"author_id,omitempty"`
+	Author      *Author     `json:"author,omitempty"`
+	Categories  []*Category `json:"categories"`
+	Tags        []*Tag      `json:"tags"`
+	Status      PostStatus  `json:"status"`
+	PublishedAt *time.Time  `json:"published_at,omitempty"`
+	// PublishAt is set while the post is scheduled to be published.
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// DeletedAt is set while the post is in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Version grows with every change and doubles as the ETag.
+	Version int `json:"version"`
 }
 
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusInReview  PostStatus = "in_review"
+	PostStatusPublished PostStatus = "published"
+	PostStatusArchived  PostStatus = "archived"
+)
+
+func (s PostStatus) Valid() bool {
+	switch s {
+	case PostStatusDraft, PostStatusInReview, PostStatusPublished, PostStatusArchived:
+		return true
+	}
+
+	return false
+}
+
+// PostInput carries the editable part of a post. Nil CategoryIDs and Tags
+// leave the existing links of an updated post untouched. AuthorID is only
+// used on creation, EditedBy is the author recorded in the revision. Version
+// is the version an update expects the post to be at and Fields limits the
+// update to the listed fields.
 type PostInput struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title       string   `json:"title"`
+	Content     string   `json:"content"`
+	AuthorID    int      `json:"author_id"`
+	CategoryIDs []int    `json:"category_ids"`
+	Tags        []string `json:"tags"`
+	EditedBy    int      `json:"-"`
+	Version     int      `json:"version"`
+	Fields      []string `json:"-"`
+	// SearchLanguage is the text search configuration a new post is indexed
+	// with, it is set by the service from its configuration.
+	SearchLanguage string `json:"-"`
+}
+
+// Fields of a post an update can be limited to.
+const (
+	PostFieldTitle       = "title"
+	PostFieldContent     = "content"
+	PostFieldCategoryIDs = "category_ids"
+	PostFieldTags        = "tags"
+)
+
+// Changes reports whether the input changes the field, an input without
+// Fields changes all of them.
+func (in *PostInput) Changes(field string) bool {
+	if len(in.Fields) == 0 {
+		return true
+	}
+
+	for _, f := range in.Fields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
 }
 
 type PostUpdateInput struct {
@@ -20,3 +88,102 @@ type PostUpdateInput struct {
 	Title   string `json:"title"`
 	Content string `json:"content"`
 }
+
+type SortField string
+
+const (
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+	SortByTitle     SortField = "title"
+)
+
+func (f SortField) Valid() bool {
+	switch f {
+	case SortByCreatedAt, SortByUpdatedAt, SortByTitle:
+		return true
+	}
+
+	return false
+}
+
+type PostSort struct {
+	Field SortField `json:"field"`
+	Desc  bool      `json:"desc"`
+}
+
+type PostFilter struct {
+	CreatedFrom   *time.Time `json:"created_from,omitempty"`
+	CreatedTo     *time.Time `json:"created_to,omitempty"`
+	UpdatedFrom   *time.Time `json:"updated_from,omitempty"`
+	UpdatedTo     *time.Time `json:"updated_to,omitempty"`
+	TitleContains string     `json:"title_contains,omitempty"`
+	Tag           string     `json:"tag,omitempty"`
+	CategoryID    int        `json:"category_id,omitempty"`
+	AuthorID      int        `json:"author_id,omitempty"`
+	// Statuses narrows the list to posts in any of the given statuses, the
+	// service only shows published posts when none are asked for.
+	Statuses []PostStatus `json:"statuses,omitempty"`
+}
+
+// Cursor points at a post in the (sort field, id) ordering of a post list.
+// Backward cursors address the page that comes before the pointed post.
+type Cursor struct {
+	Sort      PostSort  `json:"sort"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Title     string    `json:"title,omitempty"`
+	ID        int       `json:"id"`
+	Backward  bool      `json:"backward,omitempty"`
+}
+
+func NewCursor(post *Post, sort PostSort, backward bool) Cursor {
+	cursor := Cursor{Sort: sort, ID: post.ID, Backward: backward}
+
+	switch sort.Field {
+	case SortByUpdatedAt:
+		cursor.UpdatedAt = post.UpdatedAt
+	case SortByTitle:
+		cursor.Title = post.Title
+	default:
+		cursor.CreatedAt = post.CreatedAt
+	}
+
+	return cursor
+}
+
+// Value returns the sort key of the pointed post.
+func (c *Cursor) Value() interface{} {
+	switch c.Sort.Field {
+	case SortByUpdatedAt:
+		return c.UpdatedAt
+	case SortByTitle:
+		return c.Title
+	default:
+		return c.CreatedAt
+	}
+}
+
+type PostListParams struct {
+	Filter PostFilter
+	Sort   PostSort
+	Limit  int
+	Offset int
+	Cursor *Cursor
+}
+
+type PostListInput struct {
+	Filter       PostFilter `json:"filter"`
+	SortBy       string     `json:"sort_by"`
+	SortOrder    string     `json:"sort_order"`
+	Limit        int        `json:"limit"`
+	Offset       int        `json:"offset"`
+	PageToken    string     `json:"page_token"`
+	IncludeTotal bool       `json:"include_total"`
+}
+
+type PostList struct {
+	Posts         []*Post `json:"posts"`
+	NextPageToken string  `json:"next_page_token,omitempty"`
+	PrevPageToken string  `json:"prev_page_token,omitempty"`
+	TotalCount    int     `json:"total_count,omitempty"`
+}
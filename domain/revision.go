@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/kokhno-nikolay/news/pkg/diff"
+)
+
+// PostRevision is the state of a post after one of its changes. Revisions
+// are numbered per post starting from 1.
+type PostRevision struct {
+	ID        int       `json:"id"`
+	PostID    int       `json:"post_id"`
+	Revision  int       `json:"revision"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	EditedBy  int       `json:"edited_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RevisionDiff struct {
+	PostID  int         `json:"post_id"`
+	From    int         `json:"from_revision"`
+	To      int         `json:"to_revision"`
+	Title   []diff.Line `json:"title"`
+	Content []diff.Line `json:"content"`
+}
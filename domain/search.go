@@ -0,0 +1,21 @@
+package domain
+
+type SearchInput struct {
+	Query  string `json:"q"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+type SearchParams struct {
+	Query    string
+	Language string
+	Limit    int
+	Offset   int
+}
+
+type SearchResult struct {
+	Post           *Post   `json:"post"`
+	Rank           float64 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+type Category struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CategoryInput struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type Tag struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TagInput struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookEvent is what a webhook subscription is notified of. Webhooks only
+// report posts that are or were public.
+type WebhookEvent string
+
+const (
+	WebhookPostPublished   WebhookEvent = "post.published"
+	WebhookPostUpdated     WebhookEvent = "post.updated"
+	WebhookPostUnpublished WebhookEvent = "post.unpublished"
+	WebhookPostDeleted     WebhookEvent = "post.deleted"
+
+	// WebhookTest is only sent by test fires, subscriptions can not
+	// subscribe to it.
+	WebhookTest WebhookEvent = "webhook.test"
+)
+
+// WebhookEvents are the events a subscription can subscribe to.
+var WebhookEvents = []WebhookEvent{
+	WebhookPostPublished,
+	WebhookPostUpdated,
+	WebhookPostUnpublished,
+	WebhookPostDeleted,
+}
+
+func (e WebhookEvent) Valid() bool {
+	for _, event := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookSubscription is a partner endpoint notified of post events. Secret
+// signs the deliveries, it is only returned when the subscription is
+// created.
+type WebhookSubscription struct {
+	ID        int            `json:"id"`
+	URL       string         `json:"url"`
+	Secret    string         `json:"secret,omitempty"`
+	Events    []WebhookEvent `json:"events"`
+	CreatedBy int            `json:"created_by,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type WebhookInput struct {
+	URL       string         `json:"url"`
+	Events    []WebhookEvent `json:"events"`
+	Secret    string         `json:"-"`
+	CreatedBy int            `json:"-"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one payload sent to a subscription, retried until it
+// is delivered or given up. ResponseStatus is the HTTP status of the last
+// attempt, zero when the endpoint could not be reached. URL and Secret are
+// those of the subscription, they are only set for the workers.
+type WebhookDelivery struct {
+	ID             int64                 `json:"id"`
+	SubscriptionID int                   `json:"subscription_id"`
+	EventID        int64                 `json:"event_id,omitempty"`
+	Event          WebhookEvent          `json:"event"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	ResponseStatus int                   `json:"response_status,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
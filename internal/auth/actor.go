@@ -0,0 +1,61 @@
+package auth
+
+import "context"
+
+type Role string
+
+const (
+	RoleReader Role = "reader"
+	RoleAuthor Role = "author"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	RoleReader: 1,
+	RoleAuthor: 2,
+	RoleEditor: 3,
+	RoleAdmin:  4,
+}
+
+func (r Role) Valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+func (r Role) rank() int {
+	return roleRanks[r]
+}
+
+// AtLeast reports whether the role is as privileged as other.
+func (r Role) AtLeast(other Role) bool {
+	return r.Valid() && r.rank() >= other.rank()
+}
+
+// Actor is the caller of an RPC as seen by the service layer.
+type Actor struct {
+	AuthorID int
+	Role     Role
+	Claims   *Claims
+}
+
+// CanModify reports whether the actor may change content owned by authorID.
+// Editors and admins may change anything, authors only their own content.
+func (a *Actor) CanModify(authorID int) bool {
+	if a.Role.AtLeast(RoleEditor) {
+		return true
+	}
+
+	return a.Role == RoleAuthor && a.AuthorID != 0 && a.AuthorID == authorID
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) (*Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(*Actor)
+	return actor, ok && actor != nil
+}
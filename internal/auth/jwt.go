@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/kokhno-nikolay/news/config"
+)
+
+// Claims are the token claims the service understands on top of the
+// registered ones.
+type Claims struct {
+	AuthorID int  `json:"author_id,omitempty"`
+	Role     Role `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Actor turns verified claims into the caller identity used by services.
+func (c *Claims) Actor() *Actor {
+	role := c.Role
+	if role == "" {
+		role = RoleReader
+		if c.AuthorID != 0 {
+			role = RoleAuthor
+		}
+	}
+
+	return &Actor{
+		AuthorID: c.AuthorID,
+		Role:     role,
+		Claims:   c,
+	}
+}
+
+type Verifier struct {
+	parser *jwt.Parser
+	key    interface{}
+}
+
+func NewVerifier(cfg *config.Config) (*Verifier, error) {
+	var key interface{}
+
+	switch cfg.JWTAlgorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if cfg.JWTSecret == "" {
+			return nil, errors.New("JWT_SECRET is required for HS256 tokens")
+		}
+		key = []byte(cfg.JWTSecret)
+	case jwt.SigningMethodRS256.Alg():
+		pem, err := os.ReadFile(cfg.JWTPublicKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading JWT public key: %w", err)
+		}
+
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("parsing JWT public key: %w", err)
+		}
+		key = publicKey
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q, must be HS256 or RS256", cfg.JWTAlgorithm)
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{cfg.JWTAlgorithm}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.JWTIssuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.JWTIssuer))
+	}
+	if cfg.JWTAudience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.JWTAudience))
+	}
+
+	return &Verifier{
+		parser: jwt.NewParser(opts...),
+		key:    key,
+	}, nil
+}
+
+// Verify checks the token signature and standard claims.
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	var claims Claims
+
+	_, err := v.parser.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return v.key, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &claims, nil
+}
@@ -0,0 +1,64 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/internal/auth"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	cfg := &config.Config{
+		JWTAlgorithm: "HS256",
+		JWTSecret:    "test-secret",
+		JWTIssuer:    "test-issuer",
+	}
+
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		t.Fatalf("Error creating verifier: %v", err)
+	}
+
+	sign := func(method jwt.SigningMethod, key interface{}, claims auth.Claims) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("Error signing token: %v", err)
+		}
+		return token
+	}
+
+	valid := auth.Claims{
+		AuthorID: 7,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "test-issuer",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+
+	claims, err := verifier.Verify(sign(jwt.SigningMethodHS256, []byte("test-secret"), valid))
+	assert.NoError(t, err)
+	assert.Equal(t, 7, claims.AuthorID)
+	assert.Equal(t, auth.RoleAuthor, claims.Actor().Role)
+
+	// signed with another secret
+	_, err = verifier.Verify(sign(jwt.SigningMethodHS256, []byte("other-secret"), valid))
+	assert.Error(t, err)
+
+	// algorithm not configured
+	_, err = verifier.Verify(sign(jwt.SigningMethodHS512, []byte("test-secret"), valid))
+	assert.Error(t, err)
+
+	expired := valid
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	_, err = verifier.Verify(sign(jwt.SigningMethodHS256, []byte("test-secret"), expired))
+	assert.Error(t, err)
+
+	foreign := valid
+	foreign.Issuer = "someone-else"
+	_, err = verifier.Verify(sign(jwt.SigningMethodHS256, []byte("test-secret"), foreign))
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy maps full gRPC method names to the roles allowed to call them.
+type Policy struct {
+	methods map[string]map[Role]bool
+}
+
+type policyFile struct {
+	Methods map[string][]Role `yaml:"methods"`
+}
+
+func LoadPolicy(path string) (*Policy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading policy: %w", err)
+	}
+
+	var file policyFile
+	if err := yaml.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("parsing policy %s: %w", path, err)
+	}
+
+	return NewPolicy(file.Methods)
+}
+
+func NewPolicy(methods map[string][]Role) (*Policy, error) {
+	policy := &Policy{methods: make(map[string]map[Role]bool, len(methods))}
+
+	for method, roles := range methods {
+		if !strings.HasPrefix(method, "/") {
+			return nil, fmt.Errorf("policy method %q must be a full method name like /posts.Posts/Get", method)
+		}
+
+		allowed := make(map[Role]bool, len(roles))
+		for _, role := range roles {
+			if !role.Valid() {
+				return nil, fmt.Errorf("policy method %s: unknown role %q", method, role)
+			}
+			allowed[role] = true
+		}
+		policy.methods[method] = allowed
+	}
+
+	return policy, nil
+}
+
+func (p *Policy) Allowed(method string, role Role) bool {
+	return p.methods[method][role]
+}
+
+// Roles returns the roles allowed to call the method in a stable order.
+func (p *Policy) Roles(method string) []Role {
+	roles := make([]Role, 0, len(p.methods[method]))
+	for role := range p.methods[method] {
+		roles = append(roles, role)
+	}
+
+	sort.Slice(roles, func(i, j int) bool { return roles[i].rank() < roles[j].rank() })
+
+	return roles
+}
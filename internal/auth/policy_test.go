@@ -0,0 +1,55 @@
+package auth_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/internal/auth"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	policy, err := auth.LoadPolicy("../../config/policy.yaml")
+	if err != nil {
+		t.Fatalf("Error loading policy: %v", err)
+	}
+
+	assert.True(t, policy.Allowed("/posts.Posts/Get", auth.RoleReader))
+	assert.False(t, policy.Allowed("/posts.Posts/Create", auth.RoleReader))
+	assert.True(t, policy.Allowed("/posts.Posts/Create", auth.RoleAuthor))
+	assert.False(t, policy.Allowed("/posts.Authors/DeleteAuthor", auth.RoleEditor))
+	assert.True(t, policy.Allowed("/posts.Authors/DeleteAuthor", auth.RoleAdmin))
+
+	// server reflection is registered next to the services
+	assert.True(t, policy.Allowed("/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", auth.RoleReader))
+	assert.True(t, policy.Allowed("/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo", auth.RoleReader))
+
+	// methods missing from the policy are denied
+	assert.False(t, policy.Allowed("/posts.Posts/Unknown", auth.RoleAdmin))
+
+	assert.Equal(t, []auth.Role{auth.RoleAuthor, auth.RoleEditor, auth.RoleAdmin}, policy.Roles("/posts.Posts/Update"))
+}
+
+func TestLoadPolicy_UnknownRole(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte("methods:\n  /posts.Posts/Get: [owner]\n"), 0o600); err != nil {
+		t.Fatalf("Error writing policy: %v", err)
+	}
+
+	_, err := auth.LoadPolicy(path)
+	assert.Error(t, err)
+}
+
+func TestActor_CanModify(t *testing.T) {
+	author := &auth.Actor{AuthorID: 7, Role: auth.RoleAuthor}
+	assert.True(t, author.CanModify(7))
+	assert.False(t, author.CanModify(8))
+
+	reader := &auth.Actor{AuthorID: 7, Role: auth.RoleReader}
+	assert.False(t, reader.CanModify(7))
+
+	admin := &auth.Actor{Role: auth.RoleAdmin}
+	assert.True(t, admin.CanModify(8))
+}
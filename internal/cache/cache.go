@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/kokhno-nikolay/news/config"
+)
+
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// Cache stores encoded values by key. A zero ttl keeps the value until it is
+// deleted or evicted.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// New returns the cache selected by cfg.CacheBackend, or nil when none is.
+func New(cfg *config.Config) (Cache, error) {
+	switch cfg.CacheBackend {
+	case "":
+		return nil, nil
+	case BackendMemory:
+		return NewLRU(cfg.CacheSize), nil
+	case BackendRedis:
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.RedisAddress,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+
+		return NewRedis(client), nil
+	}
+
+	return nil, fmt.Errorf("unknown cache backend %q, must be one of %s, %s", cfg.CacheBackend, BackendMemory, BackendRedis)
+}
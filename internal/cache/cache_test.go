@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	c := NewLRU(2)
+	c.now = func() time.Time { return now }
+
+	assert.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	assert.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+
+	// reading a makes b the least recently used
+	value, ok, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	assert.NoError(t, c.Set(ctx, "c", []byte("3"), 0))
+	assert.Equal(t, 2, c.Len())
+
+	_, ok, _ = c.Get(ctx, "b")
+	assert.False(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok, _ = c.Get(ctx, "a")
+	assert.False(t, ok)
+
+	assert.NoError(t, c.Delete(ctx, "c", "missing"))
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+
+	c := NewRedis(redis.NewClient(&redis.Options{Addr: srv.Addr()}))
+
+	_, ok, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	assert.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+
+	value, ok, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	srv.FastForward(time.Minute)
+	_, ok, _ = c.Get(ctx, "a")
+	assert.False(t, ok)
+
+	assert.NoError(t, c.Delete(ctx, "b"))
+	_, ok, _ = c.Get(ctx, "b")
+	assert.False(t, ok)
+
+	srv.Close()
+	_, _, err = c.Get(ctx, "b")
+	assert.Error(t, err)
+
+	assert.NoError(t, c.Close())
+	_, _, err = c.Get(ctx, "b")
+	assert.ErrorIs(t, err, redis.ErrClosed)
+}
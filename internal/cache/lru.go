@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultLRUSize = 10000
+
+// LRU is an in-process cache holding up to size values, the least recently
+// used one is evicted to make room. Every replica has its own, so a change
+// made through another replica is only seen once the value expires: it is
+// only suited to a single replica, use Redis when there are more.
+type LRU struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+	now   func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewLRU(size int) *LRU {
+	if size <= 0 {
+		size = defaultLRUSize
+	}
+
+	return &LRU{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+		now:   time.Now,
+	}
+}
+
+func (c *LRU) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := el.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt) {
+		c.remove(el)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(el)
+
+	return entry.value, true, nil
+}
+
+func (c *LRU) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+func (c *LRU) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.remove(el)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of values held, expired ones included.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRU) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*lruEntry).key)
+}
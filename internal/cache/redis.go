@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis stores values in Redis, or any server speaking its protocol, shared
+// by all replicas.
+type Redis struct {
+	client redis.UniversalClient
+}
+
+func NewRedis(client redis.UniversalClient) *Redis {
+	return &Redis{
+		client: client,
+	}
+}
+
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (c *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// Close closes the connections to Redis, the cache is not usable afterwards.
+func (c *Redis) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,133 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
+
+	checkTimeout = 2 * time.Second
+)
+
+var errDirty = errors.New("the last migration failed halfway, the schema is dirty")
+
+type Check struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// MigrationsCheck reports the schema version recorded in schema_migrations.
+// A dirty version is a migration that failed halfway and needs fixing by
+// hand.
+type MigrationsCheck struct {
+	Check
+	Version int64 `json:"version"`
+	Dirty   bool  `json:"dirty"`
+}
+
+type Report struct {
+	Status     string          `json:"status"`
+	Database   Check           `json:"database"`
+	Migrations MigrationsCheck `json:"migrations"`
+}
+
+// Checker tells whether the service can take requests. It stops being
+// ready for good once draining, so the orchestrator sends no new traffic to
+// a replica that is shutting down.
+type Checker struct {
+	db       *sqlx.DB
+	draining atomic.Bool
+}
+
+func NewChecker(db *sqlx.DB) *Checker {
+	return &Checker{
+		db: db,
+	}
+}
+
+// Drain marks the service as shutting down.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Ready pings the database and reads the migration status. The service is
+// ready when both succeed, at least one migration is applied and none is
+// dirty.
+func (c *Checker) Ready(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	report := &Report{
+		Status:     StatusOK,
+		Database:   Check{Status: StatusOK},
+		Migrations: MigrationsCheck{Check: Check{Status: StatusOK}},
+	}
+
+	if err := c.db.PingContext(ctx); err != nil {
+		report.Status = StatusUnavailable
+		report.Database = Check{Status: StatusUnavailable, Error: err.Error()}
+		report.Migrations.Status = StatusUnavailable
+	} else if err := c.checkMigrations(ctx, &report.Migrations); err != nil {
+		report.Status = StatusUnavailable
+		report.Migrations.Status = StatusUnavailable
+		report.Migrations.Error = err.Error()
+	}
+
+	if c.draining.Load() {
+		report.Status = StatusDraining
+	}
+
+	return report
+}
+
+func (c *Checker) checkMigrations(ctx context.Context, check *MigrationsCheck) error {
+	err := c.db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&check.Version, &check.Dirty)
+	if err != nil {
+		return err
+	}
+
+	if check.Dirty {
+		return errDirty
+	}
+
+	return nil
+}
+
+// LiveHandler answers as long as the process serves HTTP at all.
+func LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, Check{Status: StatusOK})
+	})
+}
+
+// ReadyHandler answers 200 with the report when the service is ready and
+// 503 otherwise.
+func ReadyHandler(checker *Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := checker.Ready(r.Context())
+
+		code := http.StatusOK
+		if report.Status != StatusOK {
+			code = http.StatusServiceUnavailable
+		}
+
+		writeJSON(w, code, report)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
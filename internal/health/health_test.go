@@ -0,0 +1,101 @@
+package health_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/internal/health"
+)
+
+func TestReadyHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		mock     func(mock sqlmock.Sqlmock)
+		drain    bool
+		code     int
+		status   string
+		contains string
+	}{
+		{
+			name: "Ready",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectPing()
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations LIMIT 1").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(15, false))
+			},
+			code:     http.StatusOK,
+			status:   health.StatusOK,
+			contains: `"version":15`,
+		},
+		{
+			name: "Database down",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+			},
+			code:     http.StatusServiceUnavailable,
+			status:   health.StatusUnavailable,
+			contains: `"error":"connection refused"`,
+		},
+		{
+			name: "Dirty migration",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectPing()
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations LIMIT 1").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(15, true))
+			},
+			code:     http.StatusServiceUnavailable,
+			status:   health.StatusUnavailable,
+			contains: `"dirty":true`,
+		},
+		{
+			name: "Draining",
+			mock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectPing()
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations LIMIT 1").
+					WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(15, false))
+			},
+			drain:  true,
+			code:   http.StatusServiceUnavailable,
+			status: health.StatusDraining,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+			if err != nil {
+				t.Fatalf("Error creating mock database: %v", err)
+			}
+			defer db.Close()
+
+			tt.mock(mock)
+
+			checker := health.NewChecker(sqlx.NewDb(db, "sqlmock"))
+			if tt.drain {
+				checker.Drain()
+			}
+
+			rec := httptest.NewRecorder()
+			health.ReadyHandler(checker).ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"status":"`+tt.status+`"`)
+			assert.Contains(t, rec.Body.String(), tt.contains)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLiveHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	health.LiveHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
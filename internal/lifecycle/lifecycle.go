@@ -0,0 +1,149 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type server struct {
+	name     string
+	serve    func() error
+	shutdown func(context.Context) error
+}
+
+type worker struct {
+	name string
+	run  func(context.Context)
+}
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+// Manager runs the servers and background workers of the service until it
+// is told to stop or a server fails, then takes everything down in order:
+// drain hooks run first, servers finish their in-flight requests, workers
+// stop and closers release what is left, like the database pool.
+type Manager struct {
+	timeout    time.Duration
+	drainDelay time.Duration
+
+	drainHooks []func()
+	servers    []server
+	workers    []worker
+	closers    []closer
+}
+
+// New returns a manager that gives servers and workers timeout to stop.
+// Servers keep taking requests for drainDelay after the drain hooks ran, so
+// load balancers have time to notice the service going away.
+func New(timeout, drainDelay time.Duration) *Manager {
+	return &Manager{
+		timeout:    timeout,
+		drainDelay: drainDelay,
+	}
+}
+
+// OnDrain adds a func run as soon as the shutdown starts.
+func (m *Manager) OnDrain(fn func()) {
+	m.drainHooks = append(m.drainHooks, fn)
+}
+
+// AddServer adds a server. serve blocks until the server stops and returns
+// nil when it was stopped by shutdown, which must return once in-flight
+// requests are done or its context is. Servers are shut down in the order
+// they were added.
+func (m *Manager) AddServer(name string, serve func() error, shutdown func(context.Context) error) {
+	m.servers = append(m.servers, server{name: name, serve: serve, shutdown: shutdown})
+}
+
+// AddWorker adds a background worker, run until its context is done. Workers
+// are stopped together once the servers are down.
+func (m *Manager) AddWorker(name string, run func(context.Context)) {
+	m.workers = append(m.workers, worker{name: name, run: run})
+}
+
+// AddCloser adds a func run last, in the order closers were added.
+func (m *Manager) AddCloser(name string, close func() error) {
+	m.closers = append(m.closers, closer{name: name, close: close})
+}
+
+// Run starts the workers and servers and blocks until ctx is done or a
+// server fails, then shuts everything down. It returns the error of the
+// failed server, if any.
+func (m *Manager) Run(ctx context.Context) error {
+	// workers outlive ctx, they are stopped after the servers
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	workersDone := make(chan struct{})
+	wg := &sync.WaitGroup{}
+	wg.Add(len(m.workers))
+	for _, w := range m.workers {
+		go func(w worker) {
+			defer wg.Done()
+			w.run(workersCtx)
+		}(w)
+	}
+	go func() {
+		wg.Wait()
+		close(workersDone)
+	}()
+
+	errs := make(chan error, len(m.servers))
+	for _, s := range m.servers {
+		go func(s server) {
+			if err := s.serve(); err != nil {
+				errs <- fmt.Errorf("%s: %w", s.name, err)
+			}
+		}(s)
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+		logrus.Info("lifecycle: shutting down")
+	case err = <-errs:
+		logrus.WithError(err).Error("lifecycle: server failed, shutting down")
+	}
+
+	for _, fn := range m.drainHooks {
+		fn()
+	}
+
+	// a failed server takes nothing in anymore, no point in waiting
+	if err == nil && m.drainDelay > 0 {
+		time.Sleep(m.drainDelay)
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	for _, s := range m.servers {
+		if err := s.shutdown(stopCtx); err != nil {
+			logrus.WithError(err).WithField("server", s.name).Error("lifecycle: shutting server down")
+		}
+	}
+
+	stopWorkers()
+	select {
+	case <-workersDone:
+	case <-stopCtx.Done():
+		logrus.Error("lifecycle: workers did not stop in time")
+	}
+
+	for _, c := range m.closers {
+		if err := c.close(); err != nil {
+			logrus.WithError(err).WithField("closer", c.name).Error("lifecycle: closing")
+		}
+	}
+
+	logrus.Info("lifecycle: stopped")
+
+	return err
+}
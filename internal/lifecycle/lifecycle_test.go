@@ -0,0 +1,118 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/internal/lifecycle"
+)
+
+// recorder keeps the order in which the parts of a manager were stopped.
+type recorder struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (r *recorder) add(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step)
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.steps...)
+}
+
+// fakeServer blocks in serve until shutdown is called.
+func fakeServer(rec *recorder, name string) (func() error, func(context.Context) error) {
+	stopped := make(chan struct{})
+
+	serve := func() error {
+		<-stopped
+		return nil
+	}
+	shutdown := func(context.Context) error {
+		rec.add(name)
+		close(stopped)
+		return nil
+	}
+
+	return serve, shutdown
+}
+
+func newManager(rec *recorder) *lifecycle.Manager {
+	m := lifecycle.New(time.Second, 0)
+
+	m.OnDrain(func() { rec.add("drain") })
+	serve, shutdown := fakeServer(rec, "http")
+	m.AddServer("http", serve, shutdown)
+
+	serve, shutdown = fakeServer(rec, "grpc")
+	m.AddServer("grpc", serve, shutdown)
+	m.AddWorker("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		rec.add("worker")
+	})
+	m.AddCloser("db", func() error {
+		rec.add("db")
+		return nil
+	})
+
+	return m
+}
+
+func TestManager_Run(t *testing.T) {
+	rec := &recorder{}
+	m := newManager(rec)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- m.Run(ctx) }()
+
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("manager did not stop")
+	}
+
+	assert.Equal(t, []string{"drain", "http", "grpc", "worker", "db"}, rec.get())
+}
+
+func TestManager_Run_ServerFailure(t *testing.T) {
+	rec := &recorder{}
+	m := newManager(rec)
+
+	failure := errors.New("address already in use")
+	m.AddServer("broken", func() error { return failure }, func(context.Context) error { return nil })
+
+	err := m.Run(context.Background())
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, []string{"drain", "http", "grpc", "worker", "db"}, rec.get())
+}
+
+func TestManager_Run_Timeout(t *testing.T) {
+	m := lifecycle.New(50*time.Millisecond, 0)
+
+	closed := false
+	m.AddWorker("stuck", func(context.Context) { select {} })
+	m.AddCloser("db", func() error {
+		closed = true
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.NoError(t, m.Run(ctx))
+	assert.True(t, closed)
+}
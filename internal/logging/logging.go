@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kokhno-nikolay/news/config"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+type contextKey struct{}
+
+// Init sets up the standard logrus logger, every logger of the service
+// derives from it.
+func Init(cfg *config.Config) error {
+	level, err := logrus.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		return err
+	}
+
+	switch cfg.LogFormat {
+	case FormatJSON:
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	case FormatText:
+		logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	default:
+		return fmt.Errorf("unknown log format %q, must be one of %s, %s", cfg.LogFormat, FormatJSON, FormatText)
+	}
+
+	logrus.SetLevel(level)
+
+	return nil
+}
+
+// NewContext returns a copy of ctx carrying the logger, see FromContext.
+func NewContext(ctx context.Context, logger *logrus.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger of the request ctx belongs to, tagged
+// with its request ID, or the standard logger outside of requests.
+func FromContext(ctx context.Context) *logrus.Entry {
+	if logger, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return logger
+	}
+
+	return logrus.NewEntry(logrus.StandardLogger())
+}
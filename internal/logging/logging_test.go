@@ -0,0 +1,44 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/internal/logging"
+)
+
+func TestInit(t *testing.T) {
+	t.Cleanup(func() {
+		logrus.SetFormatter(&logrus.TextFormatter{})
+		logrus.SetLevel(logrus.InfoLevel)
+	})
+
+	assert.NoError(t, logging.Init(&config.Config{LogFormat: logging.FormatJSON, LogLevel: "debug"}))
+	assert.Equal(t, logrus.DebugLevel, logrus.GetLevel())
+
+	assert.Error(t, logging.Init(&config.Config{LogFormat: "xml", LogLevel: "info"}))
+	assert.Error(t, logging.Init(&config.Config{LogFormat: logging.FormatText, LogLevel: "loud"}))
+}
+
+func TestFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	ctx := logging.NewContext(context.Background(), logger.WithField("request_id", "abc"))
+	logging.FromContext(ctx).Info("hello")
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "abc", entry["request_id"])
+	assert.Equal(t, "hello", entry["msg"])
+
+	assert.Same(t, logrus.StandardLogger(), logging.FromContext(context.Background()).Logger)
+}
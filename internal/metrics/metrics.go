@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+)
+
+// Registry holds the metrics of the service, along with those of the Go
+// runtime and the process.
+var Registry = prometheus.NewRegistry()
+
+var (
+	rpcHandled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "RPCs completed on the server, by status code.",
+	}, []string{"grpc_service", "grpc_method", "grpc_type", "grpc_code"})
+
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Time RPCs took to complete on the server, streams included.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method", "grpc_type"})
+
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "repository_query_duration_seconds",
+		Help:    "Time repository calls took to complete, by outcome.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"repository", "method", "outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		rpcHandled,
+		rpcDuration,
+		queryDuration,
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RegisterDB exports the connection pool statistics of db, see sql.DBStats.
+func RegisterDB(db *sql.DB, name string) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveRPC records a completed RPC. fullMethod is the full gRPC method
+// name, like /posts.Posts/Get, rpcType one of unary, client_stream,
+// server_stream and bidi_stream.
+func ObserveRPC(fullMethod, rpcType string, code codes.Code, d time.Duration) {
+	service, method := splitMethod(fullMethod)
+
+	rpcHandled.WithLabelValues(service, method, rpcType, code.String()).Inc()
+	rpcDuration.WithLabelValues(service, method, rpcType).Observe(d.Seconds())
+}
+
+// ObserveQuery records a completed repository call, outcome is one of ok,
+// not_found and error.
+func ObserveQuery(repository, method, outcome string, d time.Duration) {
+	queryDuration.WithLabelValues(repository, method, outcome).Observe(d.Seconds())
+}
+
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.Index(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+
+	return "unknown", fullMethod
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestObserveRPC(t *testing.T) {
+	ObserveRPC("/posts.Posts/Get", "unary", codes.NotFound, time.Millisecond)
+	ObserveRPC("/posts.Posts/Get", "unary", codes.NotFound, time.Millisecond)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(rpcHandled.WithLabelValues("posts.Posts", "Get", "unary", "NotFound")))
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Contains(t, rec.Body.String(), `grpc_server_handling_seconds_count{grpc_method="Get",grpc_service="posts.Posts",grpc_type="unary"} 2`)
+}
+
+func TestSplitMethod(t *testing.T) {
+	service, method := splitMethod("/posts.Taxonomy/ListTags")
+	assert.Equal(t, "posts.Taxonomy", service)
+	assert.Equal(t, "ListTags", method)
+
+	service, method = splitMethod("broken")
+	assert.Equal(t, "unknown", service)
+	assert.Equal(t, "broken", method)
+}
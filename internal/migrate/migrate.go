@@ -0,0 +1,333 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// lockKey is the PostgreSQL advisory lock held while migrating, so replicas
+// started together apply every migration once.
+const lockKey = "schema_migrations"
+
+// The schema version is kept in the single row of schema_migrations, laid
+// out like golang-migrate does, so databases migrated with it carry on.
+const createVersionTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL PRIMARY KEY,
+		dirty   BOOLEAN NOT NULL
+	)
+`
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+var (
+	ErrDirty     = errors.New("the schema is dirty, a migration failed halfway: fix it by hand and force the version")
+	ErrNoDown    = errors.New("the migration can not be reverted, it has no down file")
+	ErrNoVersion = errors.New("unknown migration version")
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Migration
+	Applied bool
+}
+
+type Status struct {
+	// Version is the last applied migration, zero when there is none.
+	Version    int64
+	Dirty      bool
+	Migrations []MigrationStatus
+}
+
+// Migrator applies migrations in order of their versions. Each runs in a
+// transaction along with the version change, a failing migration leaves
+// the schema as it was.
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+func New(db *sqlx.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Load reads the migrations in the root of fsys, sorted by version. Files
+// that are not named <version>_<name>.up.sql or .down.sql are skipped.
+func Load(fsys fs.FS) ([]Migration, error) {
+	paths, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+
+	for _, p := range paths {
+		match := fileName.FindStringSubmatch(path.Base(p))
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%s: the version must be a positive number", p)
+		}
+
+		body, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("%s: version %d is already taken by %s", p, version, migration.Name)
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Up applies every migration past the current version and returns those
+// applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.locked(ctx, func(conn *sql.Conn, version int64) error {
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+
+			if err := apply(ctx, conn, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("applying %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the last n applied migrations and returns those reverted.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := m.locked(ctx, func(conn *sql.Conn, version int64) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < n; i-- {
+			migration := m.migrations[i]
+			if migration.Version > version {
+				continue
+			}
+
+			if migration.Down == "" {
+				return fmt.Errorf("reverting %d_%s: %w", migration.Version, migration.Name, ErrNoDown)
+			}
+
+			var previous int64
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+
+			if err := apply(ctx, conn, migration.Down, previous); err != nil {
+				return fmt.Errorf("reverting %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status returns the current version and which migrations are applied.
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	var migrated bool
+	if err := m.db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&migrated); err != nil {
+		return nil, err
+	}
+
+	var version int64
+	var dirty bool
+	if migrated {
+		var err error
+		if version, dirty, err = currentVersion(ctx, m.db); err != nil {
+			return nil, err
+		}
+	}
+
+	status := &Status{
+		Version:    version,
+		Dirty:      dirty,
+		Migrations: make([]MigrationStatus, len(m.migrations)),
+	}
+	for i, migration := range m.migrations {
+		status.Migrations[i] = MigrationStatus{
+			Migration: migration,
+			Applied:   migration.Version <= version,
+		}
+	}
+
+	return status, nil
+}
+
+// Force sets the version without running any migration and clears the
+// dirty flag, once a failed migration has been fixed by hand. Zero marks
+// no migration as applied.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && !m.known(version) {
+		return fmt.Errorf("%w: %d", ErrNoVersion, version)
+	}
+
+	conn, unlock, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return apply(ctx, conn, "", version)
+}
+
+func (m *Migrator) known(version int64) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// locked runs fn holding the migration lock, with the current version of a
+// schema that is not dirty.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn, version int64) error) error {
+	conn, unlock, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	version, dirty, err := currentVersion(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("version %d: %w", version, ErrDirty)
+	}
+
+	return fn(conn, version)
+}
+
+// lock takes the advisory lock on a connection of its own, the lock and
+// the migrations share the connection until unlock is called.
+func (m *Migrator) lock(ctx context.Context) (*sql.Conn, func(), error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock(hashtext($1))`, lockKey); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	unlock := func() {
+		// the lock goes with the session, were the connection lost
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, lockKey)
+		conn.Close()
+	}
+
+	if _, err := conn.ExecContext(ctx, createVersionTable); err != nil {
+		unlock()
+		return nil, nil, err
+	}
+
+	return conn, unlock, nil
+}
+
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func currentVersion(ctx context.Context, q queryer) (version int64, dirty bool, err error) {
+	err = q.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+
+	return version, dirty, err
+}
+
+// apply runs the statements of a migration and records the version the
+// schema is at afterwards, in a single transaction.
+func apply(ctx context.Context, conn *sql.Conn, statements string, version int64) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if statements != "" {
+		if _, err := tx.ExecContext(ctx, statements); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+
+	if version > 0 {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, FALSE)`, version); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
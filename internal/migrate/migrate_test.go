@@ -0,0 +1,203 @@
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/internal/migrate"
+	"github.com/kokhno-nikolay/news/migrations"
+)
+
+var testFS = fstest.MapFS{
+	"0001_create_posts.up.sql":   {Data: []byte("CREATE TABLE posts (id INT)")},
+	"0001_create_posts.down.sql": {Data: []byte("DROP TABLE posts")},
+	"0003_add_title.up.sql":      {Data: []byte("ALTER TABLE posts ADD title TEXT")},
+	"0003_add_title.down.sql":    {Data: []byte("ALTER TABLE posts DROP title")},
+	"0004_add_index.up.sql":      {Data: []byte("CREATE INDEX posts_title_idx ON posts (title)")},
+	"README.md":                  {Data: []byte("not a migration")},
+}
+
+func newMigrator(t *testing.T) (*migrate.Migrator, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrate.New(sqlx.NewDb(db, "sqlmock"), testFS)
+	if err != nil {
+		t.Fatalf("Error loading migrations: %v", err)
+	}
+
+	return migrator, mock
+}
+
+func expectLock(mock sqlmock.Sqlmock, version int64, dirty bool) {
+	mock.ExpectExec("SELECT pg_advisory_lock\\(hashtext\\(\\$1\\)\\)").WithArgs("schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version", "dirty"})
+	if version > 0 {
+		rows.AddRow(version, dirty)
+	}
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations LIMIT 1").WillReturnRows(rows)
+}
+
+func expectApply(mock sqlmock.Sqlmock, statement string, version int64) {
+	mock.ExpectBegin()
+	mock.ExpectExec(statement).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(sqlmock.NewResult(0, 1))
+	if version > 0 {
+		mock.ExpectExec("INSERT INTO schema_migrations \\(version, dirty\\) VALUES \\(\\$1, FALSE\\)").WithArgs(version).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT pg_advisory_unlock\\(hashtext\\(\\$1\\)\\)").WithArgs("schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestLoad(t *testing.T) {
+	loaded, err := migrate.Load(testFS)
+	assert.NoError(t, err)
+	if assert.Len(t, loaded, 3) {
+		assert.Equal(t, int64(1), loaded[0].Version)
+		assert.Equal(t, "create_posts", loaded[0].Name)
+		assert.Equal(t, int64(3), loaded[1].Version)
+		assert.Equal(t, int64(4), loaded[2].Version)
+		assert.Empty(t, loaded[2].Down)
+	}
+
+	_, err = migrate.Load(fstest.MapFS{"0001_a.down.sql": {Data: []byte("DROP TABLE a")}})
+	assert.Error(t, err)
+
+	_, err = migrate.Load(fstest.MapFS{
+		"0001_a.up.sql": {Data: []byte("CREATE TABLE a (id INT)")},
+		"0001_b.up.sql": {Data: []byte("CREATE TABLE b (id INT)")},
+	})
+	assert.Error(t, err)
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	loaded, err := migrate.Load(migrations.FS)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, loaded)
+
+	for i, migration := range loaded {
+		assert.NotEmpty(t, migration.Down, "migration %d has no down file", migration.Version)
+		if i > 0 {
+			assert.Greater(t, migration.Version, loaded[i-1].Version)
+		}
+	}
+}
+
+func TestMigrator_Up(t *testing.T) {
+	migrator, mock := newMigrator(t)
+
+	expectLock(mock, 1, false)
+	expectApply(mock, "ALTER TABLE posts ADD title TEXT", 3)
+	expectApply(mock, "CREATE INDEX posts_title_idx ON posts \\(title\\)", 4)
+	expectUnlock(mock)
+
+	applied, err := migrator.Up(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, applied, 2) {
+		assert.Equal(t, int64(3), applied[0].Version)
+		assert.Equal(t, int64(4), applied[1].Version)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_Failure(t *testing.T) {
+	migrator, mock := newMigrator(t)
+
+	expectLock(mock, 1, false)
+	mock.ExpectBegin()
+	mock.ExpectExec("ALTER TABLE posts ADD title TEXT").WillReturnError(errors.New("syntax error"))
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	applied, err := migrator.Up(context.Background())
+	assert.ErrorContains(t, err, "applying 3_add_title: syntax error")
+	assert.Empty(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Up_Dirty(t *testing.T) {
+	migrator, mock := newMigrator(t)
+
+	expectLock(mock, 3, true)
+	expectUnlock(mock)
+
+	_, err := migrator.Up(context.Background())
+	assert.ErrorIs(t, err, migrate.ErrDirty)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	migrator, mock := newMigrator(t)
+
+	expectLock(mock, 3, false)
+	expectApply(mock, "ALTER TABLE posts DROP title", 1)
+	expectApply(mock, "DROP TABLE posts", 0)
+	expectUnlock(mock)
+
+	reverted, err := migrator.Down(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.Len(t, reverted, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down_NoDownFile(t *testing.T) {
+	migrator, mock := newMigrator(t)
+
+	expectLock(mock, 4, false)
+	expectUnlock(mock)
+
+	_, err := migrator.Down(context.Background(), 1)
+	assert.ErrorIs(t, err, migrate.ErrNoDown)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status(t *testing.T) {
+	migrator, mock := newMigrator(t)
+
+	mock.ExpectQuery("SELECT to_regclass\\('schema_migrations'\\) IS NOT NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations LIMIT 1").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(3, false))
+
+	status, err := migrator.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), status.Version)
+	assert.False(t, status.Dirty)
+	if assert.Len(t, status.Migrations, 3) {
+		assert.True(t, status.Migrations[0].Applied)
+		assert.True(t, status.Migrations[1].Applied)
+		assert.False(t, status.Migrations[2].Applied)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Force(t *testing.T) {
+	migrator, mock := newMigrator(t)
+
+	assert.ErrorIs(t, migrator.Force(context.Background(), 2), migrate.ErrNoVersion)
+
+	mock.ExpectExec("SELECT pg_advisory_lock\\(hashtext\\(\\$1\\)\\)").WithArgs("schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO schema_migrations \\(version, dirty\\) VALUES \\(\\$1, FALSE\\)").WithArgs(int64(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	assert.NoError(t, migrator.Force(context.Background(), 3))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,143 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/domain"
+)
+
+const (
+	PublisherStdout  = "stdout"
+	PublisherFile    = "file"
+	PublisherWebhook = "webhook"
+
+	webhookTimeout = 10 * time.Second
+)
+
+// Publisher delivers post events to other services. Delivery is at least
+// once, consumers tell repeated events apart by their id.
+type Publisher interface {
+	Publish(ctx context.Context, event *domain.PostEvent) error
+}
+
+// NewPublisher returns the publisher selected by cfg.OutboxPublisher, or nil
+// when none is.
+func NewPublisher(cfg *config.Config) (Publisher, error) {
+	switch cfg.OutboxPublisher {
+	case "":
+		return nil, nil
+	case PublisherStdout:
+		return NewWriterPublisher(os.Stdout), nil
+	case PublisherFile:
+		f, err := os.OpenFile(cfg.OutboxFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewWriterPublisher(f), nil
+	case PublisherWebhook:
+		if cfg.OutboxWebhookURL == "" {
+			return nil, fmt.Errorf("OUTBOX_WEBHOOK_URL is required by the %s publisher", PublisherWebhook)
+		}
+
+		return NewWebhookPublisher(cfg.OutboxWebhookURL), nil
+	}
+
+	return nil, fmt.Errorf("unknown outbox publisher %q, must be one of %s, %s, %s", cfg.OutboxPublisher, PublisherStdout, PublisherFile, PublisherWebhook)
+}
+
+// WriterPublisher writes every event as a line of JSON.
+type WriterPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{
+		w: w,
+	}
+}
+
+func (p *WriterPublisher) Publish(_ context.Context, event *domain.PostEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.w.Write(append(line, '\n'))
+
+	return err
+}
+
+// WebhookPublisher posts every event as JSON to a URL, any status but 2xx
+// fails the delivery.
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookPublisher(url string) *WebhookPublisher {
+	return &WebhookPublisher{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, event *domain.PostEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatInt(event.ID, 10))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// drained so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+
+	return nil
+}
+
+// MultiPublisher delivers every event to all of its publishers. An event
+// that fails with any of them is published to all of them again, so each
+// must be fine with repeated events.
+type MultiPublisher []Publisher
+
+func (m MultiPublisher) Publish(ctx context.Context, event *domain.PostEvent) error {
+	var errs []error
+	for _, publisher := range m {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
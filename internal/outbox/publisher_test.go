@@ -0,0 +1,46 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/domain"
+)
+
+func TestWriterPublisher_Publish(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := NewWriterPublisher(&buf)
+
+	err := publisher.Publish(context.Background(), &domain.PostEvent{ID: 7, Type: domain.PostCreated, PostID: 1, Payload: json.RawMessage(`{"id":1}`)})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":7,"type":"created","post_id":1,"published":false,"created_at":"0001-01-01T00:00:00Z","payload":{"id":1}}`, buf.String())
+	assert.Equal(t, byte('\n'), buf.Bytes()[buf.Len()-1])
+}
+
+func TestWebhookPublisher_Publish(t *testing.T) {
+	var got domain.PostEvent
+	status := http.StatusNoContent
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "7", r.Header.Get("X-Event-ID"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	publisher := NewWebhookPublisher(srv.URL)
+	event := &domain.PostEvent{ID: 7, Type: domain.PostDeleted, PostID: 1}
+
+	assert.NoError(t, publisher.Publish(context.Background(), event))
+	assert.Equal(t, domain.PostDeleted, got.Type)
+
+	status = http.StatusServiceUnavailable
+	assert.Error(t, publisher.Publish(context.Background(), event))
+}
@@ -0,0 +1,124 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/internal/repository"
+	"github.com/kokhno-nikolay/news/pkg/backoff"
+)
+
+const (
+	defaultRelayInterval  = 10 * time.Second
+	defaultRelayBatchSize = 100
+	defaultMaxAttempts    = 10
+
+	// claimLease hides claimed events from other relays while they are
+	// being published.
+	claimLease = 5 * time.Minute
+
+	minBackoff = time.Second
+	maxBackoff = time.Hour
+)
+
+// Relay delivers the events of the outbox to a publisher. It is woken up by
+// new events and also checks every interval for events due to be retried.
+// Replicas claim disjoint events, so it is safe to run on every one of them.
+type Relay struct {
+	repo        repository.Outbox
+	notifier    repository.PostNotifier
+	publisher   Publisher
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+func NewRelay(repo repository.Outbox, notifier repository.PostNotifier, publisher Publisher, cfg *config.Config) *Relay {
+	r := &Relay{
+		repo:        repo,
+		notifier:    notifier,
+		publisher:   publisher,
+		interval:    cfg.OutboxInterval,
+		batchSize:   cfg.OutboxBatchSize,
+		maxAttempts: cfg.OutboxMaxAttempts,
+	}
+
+	if r.interval <= 0 {
+		r.interval = defaultRelayInterval
+	}
+
+	if r.batchSize <= 0 {
+		r.batchSize = defaultRelayBatchSize
+	}
+
+	if r.batchSize > repository.MaxLimit {
+		r.batchSize = repository.MaxLimit
+	}
+
+	if r.maxAttempts <= 0 {
+		r.maxAttempts = defaultMaxAttempts
+	}
+
+	return r
+}
+
+// Run delivers events until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		changed := r.notifier.Changed()
+
+		r.relayPending(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) relayPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		events, err := r.repo.Claim(ctx, r.batchSize, claimLease)
+		if err != nil {
+			logrus.WithError(err).Error("outbox: claiming events")
+			return
+		}
+
+		for _, event := range events {
+			if err := r.publisher.Publish(ctx, event); err != nil {
+				r.markFailed(ctx, event.ID, event.Attempts, err)
+				continue
+			}
+
+			if err := r.repo.MarkDelivered(ctx, event.ID); err != nil {
+				logrus.WithError(err).WithField("event_id", event.ID).Error("outbox: marking event delivered")
+			}
+		}
+
+		if len(events) < r.batchSize {
+			return
+		}
+	}
+}
+
+func (r *Relay) markFailed(ctx context.Context, id int64, attempts int, cause error) {
+	var retryAt *time.Time
+	if attempts < r.maxAttempts {
+		at := time.Now().Add(backoff.Exponential(attempts, minBackoff, maxBackoff))
+		retryAt = &at
+	} else {
+		logrus.WithError(cause).WithFields(logrus.Fields{"event_id": id, "attempts": attempts}).Warn("outbox: giving event up")
+	}
+
+	if err := r.repo.MarkFailed(ctx, id, cause.Error(), retryAt); err != nil {
+		logrus.WithError(err).WithField("event_id", id).Error("outbox: marking event failed")
+	}
+}
@@ -0,0 +1,96 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/domain"
+	mock_repository "github.com/kokhno-nikolay/news/internal/repository/mocks"
+)
+
+type publisherFunc func(ctx context.Context, event *domain.PostEvent) error
+
+func (f publisherFunc) Publish(ctx context.Context, event *domain.PostEvent) error {
+	return f(ctx, event)
+}
+
+var errUnavailable = errors.New("broker unavailable")
+
+// failing fails the events with the given ids and publishes all others.
+func failing(ids ...int64) Publisher {
+	return publisherFunc(func(_ context.Context, event *domain.PostEvent) error {
+		for _, id := range ids {
+			if event.ID == id {
+				return errUnavailable
+			}
+		}
+		return nil
+	})
+}
+
+func newRelay(t *testing.T, publisher Publisher) (*Relay, *mock_repository.MockOutbox) {
+	repo := mock_repository.NewMockOutbox(gomock.NewController(t))
+
+	return NewRelay(repo, nil, publisher, &config.Config{OutboxBatchSize: 2, OutboxMaxAttempts: 3}), repo
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+	t.Run("delivered", func(t *testing.T) {
+		r, repo := newRelay(t, failing())
+
+		repo.EXPECT().Claim(gomock.Any(), 2, claimLease).Return([]*domain.PostEvent{{ID: 1, Attempts: 1}}, nil)
+		repo.EXPECT().MarkDelivered(gomock.Any(), int64(1)).Return(nil)
+
+		r.relayPending(context.Background())
+	})
+
+	t.Run("retried with a growing delay", func(t *testing.T) {
+		r, repo := newRelay(t, failing(1))
+
+		repo.EXPECT().Claim(gomock.Any(), 2, claimLease).Return([]*domain.PostEvent{{ID: 1, Attempts: 2}}, nil)
+		repo.EXPECT().MarkFailed(gomock.Any(), int64(1), errUnavailable.Error(), gomock.Not(gomock.Nil())).
+			DoAndReturn(func(_ context.Context, _ int64, _ string, retryAt *time.Time) error {
+				// the second attempt waits twice the first delay
+				assert.WithinDuration(t, time.Now().Add(2*minBackoff), *retryAt, time.Second)
+				return nil
+			})
+
+		r.relayPending(context.Background())
+	})
+
+	t.Run("given up after max attempts", func(t *testing.T) {
+		r, repo := newRelay(t, failing(1))
+
+		repo.EXPECT().Claim(gomock.Any(), 2, claimLease).Return([]*domain.PostEvent{{ID: 1, Attempts: 3}}, nil)
+		repo.EXPECT().MarkFailed(gomock.Any(), int64(1), errUnavailable.Error(), gomock.Nil()).Return(nil)
+
+		r.relayPending(context.Background())
+	})
+
+	t.Run("drains all pages", func(t *testing.T) {
+		r, repo := newRelay(t, failing(2))
+
+		gomock.InOrder(
+			repo.EXPECT().Claim(gomock.Any(), 2, claimLease).Return([]*domain.PostEvent{{ID: 1, Attempts: 1}, {ID: 2, Attempts: 1}}, nil),
+			repo.EXPECT().Claim(gomock.Any(), 2, claimLease).Return(nil, nil),
+		)
+		repo.EXPECT().MarkDelivered(gomock.Any(), int64(1)).Return(nil)
+		repo.EXPECT().MarkFailed(gomock.Any(), int64(2), errUnavailable.Error(), gomock.Not(gomock.Nil())).Return(nil)
+
+		r.relayPending(context.Background())
+	})
+
+	t.Run("stops on claim error", func(t *testing.T) {
+		r, repo := newRelay(t, failing())
+
+		repo.EXPECT().Claim(gomock.Any(), 2, claimLease).Return(nil, errors.New("connection refused"))
+
+		r.relayPending(context.Background())
+	})
+}
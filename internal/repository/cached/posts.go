@@ -0,0 +1,274 @@
+package cached
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/cache"
+	"github.com/kokhno-nikolay/news/internal/logging"
+	"github.com/kokhno-nikolay/news/internal/repository"
+)
+
+// listGenerationKey holds the generation of cached post lists. Lists are
+// cached under the current generation, a change of any post starts a new
+// one, which leaves the older lists to expire.
+const listGenerationKey = "posts:list:generation"
+
+// loadTimeout bounds a load shared by concurrent callers, it does not end
+// with the request of the caller that started it.
+const loadTimeout = 10 * time.Second
+
+// PostRepo is a read-through cache of Get and List in front of another
+// repository.Posts, the other methods are passed through. Loads of the same
+// key are shared by concurrent callers, so an expired hot key hits the
+// database once. Writes through PostRepo drop the cached post and all
+// cached lists, changes of categories, tags and authors embedded in posts
+// are only seen once the ttl is over. A cache that fails is bypassed.
+type PostRepo struct {
+	repository.Posts
+	cache cache.Cache
+	ttl   time.Duration
+	group singleflight.Group
+}
+
+func NewPostRepo(posts repository.Posts, c cache.Cache, ttl time.Duration) *PostRepo {
+	return &PostRepo{
+		Posts: posts,
+		cache: c,
+		ttl:   ttl,
+	}
+}
+
+func (r *PostRepo) Get(ctx context.Context, id int) (*domain.Post, error) {
+	var post domain.Post
+	err := r.load(ctx, postKey(id), &post, func(ctx context.Context) (interface{}, error) {
+		return r.Posts.Get(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &post, nil
+}
+
+func (r *PostRepo) List(ctx context.Context, params *domain.PostListParams) ([]*domain.Post, error) {
+	generation, err := r.listGeneration(ctx)
+	if err != nil {
+		return r.Posts.List(ctx, params)
+	}
+
+	key, err := listKey(generation, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var posts []*domain.Post
+	err = r.load(ctx, key, &posts, func(ctx context.Context) (interface{}, error) {
+		return r.Posts.List(ctx, params)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (r *PostRepo) Create(ctx context.Context, input *domain.PostInput) (*domain.Post, error) {
+	post, err := r.Posts.Create(ctx, input)
+	if err == nil {
+		r.invalidate(ctx)
+	}
+
+	return post, err
+}
+
+func (r *PostRepo) Update(ctx context.Context, id int, input *domain.PostInput) (*domain.Post, error) {
+	post, err := r.Posts.Update(ctx, id, input)
+	if err == nil {
+		r.invalidate(ctx, id)
+	}
+
+	return post, err
+}
+
+func (r *PostRepo) UpdateStatus(ctx context.Context, id int, from, to domain.PostStatus) (*domain.Post, error) {
+	post, err := r.Posts.UpdateStatus(ctx, id, from, to)
+	if err == nil {
+		r.invalidate(ctx, id)
+	}
+
+	return post, err
+}
+
+func (r *PostRepo) SetPublishAt(ctx context.Context, id int, status domain.PostStatus, publishAt *time.Time) (*domain.Post, error) {
+	post, err := r.Posts.SetPublishAt(ctx, id, status, publishAt)
+	if err == nil {
+		r.invalidate(ctx, id)
+	}
+
+	return post, err
+}
+
+func (r *PostRepo) PublishDue(ctx context.Context, now time.Time, limit int) ([]int, error) {
+	ids, err := r.Posts.PublishDue(ctx, now, limit)
+	if err == nil && len(ids) > 0 {
+		r.invalidate(ctx, ids...)
+	}
+
+	return ids, err
+}
+
+func (r *PostRepo) Restore(ctx context.Context, id int) (*domain.Post, error) {
+	post, err := r.Posts.Restore(ctx, id)
+	if err == nil {
+		r.invalidate(ctx, id)
+	}
+
+	return post, err
+}
+
+func (r *PostRepo) Delete(ctx context.Context, id int) (bool, error) {
+	deleted, err := r.Posts.Delete(ctx, id)
+	if err == nil && deleted {
+		r.invalidate(ctx, id)
+	}
+
+	return deleted, err
+}
+
+func (r *PostRepo) CreateBatch(ctx context.Context, inputs []*domain.PostInput) ([]*domain.Post, error) {
+	posts, err := r.Posts.CreateBatch(ctx, inputs)
+	if err == nil && len(posts) > 0 {
+		r.invalidate(ctx)
+	}
+
+	return posts, err
+}
+
+func (r *PostRepo) UpdateBatch(ctx context.Context, updates []*domain.PostBatchUpdate) ([]*domain.Post, error) {
+	posts, err := r.Posts.UpdateBatch(ctx, updates)
+	if err == nil && len(posts) > 0 {
+		ids := make([]int, len(posts))
+		for i, post := range posts {
+			ids[i] = post.ID
+		}
+		r.invalidate(ctx, ids...)
+	}
+
+	return posts, err
+}
+
+func (r *PostRepo) DeleteBatch(ctx context.Context, ids []int) ([]int, error) {
+	deleted, err := r.Posts.DeleteBatch(ctx, ids)
+	if err == nil && len(deleted) > 0 {
+		r.invalidate(ctx, deleted...)
+	}
+
+	return deleted, err
+}
+
+// load decodes the value cached under key into dst. On a miss the value is
+// fetched, once for all concurrent callers, and cached for the ttl. The
+// fetch outlives the caller that started it, a canceled request does not
+// fail the others waiting on it. Every caller decodes its own copy, so they
+// can not change each other's posts.
+func (r *PostRepo) load(ctx context.Context, key string, dst interface{}, fetch func(ctx context.Context) (interface{}, error)) error {
+	value, ok, err := r.cache.Get(ctx, key)
+	if err != nil || !ok {
+		ch := r.group.DoChan(key, func() (interface{}, error) {
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+			defer cancel()
+
+			res, err := fetch(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			encoded, err := json.Marshal(res)
+			if err != nil {
+				return nil, err
+			}
+
+			// failing to cache only costs the next caller a load
+			_ = r.cache.Set(ctx, key, encoded, r.ttl)
+
+			return encoded, nil
+		})
+
+		var res singleflight.Result
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res = <-ch:
+		}
+
+		if res.Err != nil {
+			return res.Err
+		}
+
+		value = res.Val.([]byte)
+	}
+
+	return json.Unmarshal(value, dst)
+}
+
+func (r *PostRepo) listGeneration(ctx context.Context) (string, error) {
+	generation, ok, err := r.cache.Get(ctx, listGenerationKey)
+	if err != nil {
+		return "", err
+	}
+
+	if ok {
+		return string(generation), nil
+	}
+
+	return r.newListGeneration(ctx)
+}
+
+// newListGeneration starts a generation that was never used before, so a
+// generation that was evicted can not bring its lists back.
+func (r *PostRepo) newListGeneration(ctx context.Context) (string, error) {
+	generation := strconv.FormatInt(time.Now().UnixNano(), 36)
+
+	return generation, r.cache.Set(ctx, listGenerationKey, []byte(generation), 0)
+}
+
+// invalidate drops the cached posts with the given ids and all cached
+// lists. A load that started before the change may still cache the old
+// post, which then lasts until the ttl is over.
+func (r *PostRepo) invalidate(ctx context.Context, ids ...int) {
+	if len(ids) > 0 {
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = postKey(id)
+		}
+
+		if err := r.cache.Delete(ctx, keys...); err != nil {
+			logging.FromContext(ctx).WithError(err).WithField("post_ids", ids).Warn("cache: dropping posts")
+		}
+	}
+
+	if _, err := r.newListGeneration(ctx); err != nil {
+		logging.FromContext(ctx).WithError(err).Warn("cache: dropping post lists")
+	}
+}
+
+func postKey(id int) string {
+	return "posts:" + strconv.Itoa(id)
+}
+
+func listKey(generation string, params *domain.PostListParams) (string, error) {
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("posts:list:%s:%x", generation, sha256.Sum256(encoded)), nil
+}
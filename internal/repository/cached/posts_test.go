@@ -0,0 +1,154 @@
+package cached_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/cache"
+	"github.com/kokhno-nikolay/news/internal/repository/cached"
+	"github.com/kokhno-nikolay/news/internal/repository/mocks"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+func TestPostRepo_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	posts := mock_repository.NewMockPosts(ctrl)
+	repo := cached.NewPostRepo(posts, cache.NewLRU(100), time.Minute)
+	ctx := context.Background()
+
+	posts.EXPECT().Get(gomock.Any(), 1).Return(&domain.Post{ID: 1, Title: "First"}, nil).Times(1)
+
+	for i := 0; i < 3; i++ {
+		post, err := repo.Get(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "First", post.Title)
+	}
+
+	// callers get their own copy
+	post, _ := repo.Get(ctx, 1)
+	post.Title = "Changed"
+	post, _ = repo.Get(ctx, 1)
+	assert.Equal(t, "First", post.Title)
+
+	// errors are not cached
+	posts.EXPECT().Get(gomock.Any(), 2).Return(nil, errors.ErrNotFound).Times(2)
+
+	_, err := repo.Get(ctx, 2)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	_, err = repo.Get(ctx, 2)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestPostRepo_Get_Singleflight(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	posts := mock_repository.NewMockPosts(ctrl)
+	repo := cached.NewPostRepo(posts, cache.NewLRU(100), time.Minute)
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	posts.EXPECT().Get(gomock.Any(), 1).DoAndReturn(func(context.Context, int) (*domain.Post, error) {
+		<-release
+		return &domain.Post{ID: 1}, nil
+	}).Times(1)
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			post, err := repo.Get(ctx, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, post.ID)
+		}()
+	}
+
+	// lets the callers pile up behind the first load
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+}
+
+func TestPostRepo_Invalidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	posts := mock_repository.NewMockPosts(ctrl)
+	repo := cached.NewPostRepo(posts, cache.NewLRU(100), time.Minute)
+	ctx := context.Background()
+
+	params := &domain.PostListParams{Limit: 10}
+	input := &domain.PostInput{Title: "Second"}
+
+	gomock.InOrder(
+		posts.EXPECT().Get(gomock.Any(), 1).Return(&domain.Post{ID: 1, Title: "First"}, nil),
+		posts.EXPECT().List(gomock.Any(), params).Return([]*domain.Post{{ID: 1}}, nil),
+		posts.EXPECT().Update(ctx, 1, input).Return(&domain.Post{ID: 1, Title: "Second"}, nil),
+		posts.EXPECT().Get(gomock.Any(), 1).Return(&domain.Post{ID: 1, Title: "Second"}, nil),
+		posts.EXPECT().List(gomock.Any(), params).Return([]*domain.Post{{ID: 1}, {ID: 2}}, nil),
+	)
+
+	_, _ = repo.Get(ctx, 1)
+	_, _ = repo.List(ctx, params)
+
+	list, err := repo.List(ctx, params)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	_, err = repo.Update(ctx, 1, input)
+	assert.NoError(t, err)
+
+	post, err := repo.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Second", post.Title)
+
+	list, err = repo.List(ctx, params)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+}
+
+func TestPostRepo_Get_CanceledCaller(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	posts := mock_repository.NewMockPosts(ctrl)
+	repo := cached.NewPostRepo(posts, cache.NewLRU(100), time.Minute)
+
+	release := make(chan struct{})
+	posts.EXPECT().Get(gomock.Any(), 1).DoAndReturn(func(ctx context.Context, _ int) (*domain.Post, error) {
+		<-release
+		return &domain.Post{ID: 1}, ctx.Err()
+	}).Times(1)
+
+	// the first caller gives up while the load it started is running
+	first, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := repo.Get(first, 1)
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	second := make(chan *domain.Post)
+	go func() {
+		post, err := repo.Get(context.Background(), 1)
+		assert.NoError(t, err)
+		second <- post
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	close(release)
+	post := <-second
+	assert.Equal(t, 1, post.ID)
+
+	// and the value is cached for the next caller
+	post, err := repo.Get(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, post.ID)
+}
@@ -0,0 +1,235 @@
+package instrumented
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/metrics"
+	"github.com/kokhno-nikolay/news/internal/repository"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+var tracer = otel.Tracer("github.com/kokhno-nikolay/news/internal/repository")
+
+// PostRepo times and traces every call of another repository.Posts. Put
+// below the cache, it only sees the calls that reach the database.
+type PostRepo struct {
+	repository.Posts
+}
+
+func NewPostRepo(posts repository.Posts) *PostRepo {
+	return &PostRepo{
+		Posts: posts,
+	}
+}
+
+func (r *PostRepo) Get(ctx context.Context, id int) (_ *domain.Post, err error) {
+	ctx, finish := r.start(ctx, "get")
+	defer finish(&err)
+
+	return r.Posts.Get(ctx, id)
+}
+
+func (r *PostRepo) List(ctx context.Context, params *domain.PostListParams) (_ []*domain.Post, err error) {
+	ctx, finish := r.start(ctx, "list")
+	defer finish(&err)
+
+	return r.Posts.List(ctx, params)
+}
+
+func (r *PostRepo) Count(ctx context.Context, filter *domain.PostFilter) (_ int, err error) {
+	ctx, finish := r.start(ctx, "count")
+	defer finish(&err)
+
+	return r.Posts.Count(ctx, filter)
+}
+
+func (r *PostRepo) Search(ctx context.Context, params *domain.SearchParams) (_ []*domain.SearchResult, err error) {
+	ctx, finish := r.start(ctx, "search")
+	defer finish(&err)
+
+	return r.Posts.Search(ctx, params)
+}
+
+func (r *PostRepo) Create(ctx context.Context, input *domain.PostInput) (_ *domain.Post, err error) {
+	ctx, finish := r.start(ctx, "create")
+	defer finish(&err)
+
+	return r.Posts.Create(ctx, input)
+}
+
+func (r *PostRepo) Update(ctx context.Context, id int, input *domain.PostInput) (_ *domain.Post, err error) {
+	ctx, finish := r.start(ctx, "update")
+	defer finish(&err)
+
+	return r.Posts.Update(ctx, id, input)
+}
+
+func (r *PostRepo) UpdateStatus(ctx context.Context, id int, from, to domain.PostStatus) (_ *domain.Post, err error) {
+	ctx, finish := r.start(ctx, "update_status")
+	defer finish(&err)
+
+	return r.Posts.UpdateStatus(ctx, id, from, to)
+}
+
+func (r *PostRepo) SetPublishAt(ctx context.Context, id int, status domain.PostStatus, publishAt *time.Time) (_ *domain.Post, err error) {
+	ctx, finish := r.start(ctx, "set_publish_at")
+	defer finish(&err)
+
+	return r.Posts.SetPublishAt(ctx, id, status, publishAt)
+}
+
+func (r *PostRepo) PublishDue(ctx context.Context, now time.Time, limit int) (_ []int, err error) {
+	ctx, finish := r.start(ctx, "publish_due")
+	defer finish(&err)
+
+	return r.Posts.PublishDue(ctx, now, limit)
+}
+
+func (r *PostRepo) ListRevisions(ctx context.Context, postID, limit, offset int) (_ []*domain.PostRevision, err error) {
+	ctx, finish := r.start(ctx, "list_revisions")
+	defer finish(&err)
+
+	return r.Posts.ListRevisions(ctx, postID, limit, offset)
+}
+
+func (r *PostRepo) GetRevision(ctx context.Context, postID, revision int) (_ *domain.PostRevision, err error) {
+	ctx, finish := r.start(ctx, "get_revision")
+	defer finish(&err)
+
+	return r.Posts.GetRevision(ctx, postID, revision)
+}
+
+func (r *PostRepo) GetTrashed(ctx context.Context, id int) (_ *domain.Post, err error) {
+	ctx, finish := r.start(ctx, "get_trashed")
+	defer finish(&err)
+
+	return r.Posts.GetTrashed(ctx, id)
+}
+
+func (r *PostRepo) ListTrash(ctx context.Context, authorID, limit, offset int) (_ []*domain.Post, err error) {
+	ctx, finish := r.start(ctx, "list_trash")
+	defer finish(&err)
+
+	return r.Posts.ListTrash(ctx, authorID, limit, offset)
+}
+
+func (r *PostRepo) Restore(ctx context.Context, id int) (_ *domain.Post, err error) {
+	ctx, finish := r.start(ctx, "restore")
+	defer finish(&err)
+
+	return r.Posts.Restore(ctx, id)
+}
+
+func (r *PostRepo) Purge(ctx context.Context, id int) (_ bool, err error) {
+	ctx, finish := r.start(ctx, "purge")
+	defer finish(&err)
+
+	return r.Posts.Purge(ctx, id)
+}
+
+func (r *PostRepo) PurgeTrashed(ctx context.Context, before time.Time, limit int) (_ int, err error) {
+	ctx, finish := r.start(ctx, "purge_trashed")
+	defer finish(&err)
+
+	return r.Posts.PurgeTrashed(ctx, before, limit)
+}
+
+func (r *PostRepo) Delete(ctx context.Context, id int) (_ bool, err error) {
+	ctx, finish := r.start(ctx, "delete")
+	defer finish(&err)
+
+	return r.Posts.Delete(ctx, id)
+}
+
+func (r *PostRepo) GetBatch(ctx context.Context, ids []int) (_ []*domain.Post, err error) {
+	ctx, finish := r.start(ctx, "get_batch")
+	defer finish(&err)
+
+	return r.Posts.GetBatch(ctx, ids)
+}
+
+func (r *PostRepo) CreateBatch(ctx context.Context, inputs []*domain.PostInput) (_ []*domain.Post, err error) {
+	ctx, finish := r.start(ctx, "create_batch")
+	defer finish(&err)
+
+	return r.Posts.CreateBatch(ctx, inputs)
+}
+
+func (r *PostRepo) UpdateBatch(ctx context.Context, updates []*domain.PostBatchUpdate) (_ []*domain.Post, err error) {
+	ctx, finish := r.start(ctx, "update_batch")
+	defer finish(&err)
+
+	return r.Posts.UpdateBatch(ctx, updates)
+}
+
+func (r *PostRepo) DeleteBatch(ctx context.Context, ids []int) (_ []int, err error) {
+	ctx, finish := r.start(ctx, "delete_batch")
+	defer finish(&err)
+
+	return r.Posts.DeleteBatch(ctx, ids)
+}
+
+func (r *PostRepo) ListEvents(ctx context.Context, afterID int64, limit int) (_ []*domain.PostEvent, err error) {
+	ctx, finish := r.start(ctx, "list_events")
+	defer finish(&err)
+
+	return r.Posts.ListEvents(ctx, afterID, limit)
+}
+
+func (r *PostRepo) EventBounds(ctx context.Context) (first, last int64, err error) {
+	ctx, finish := r.start(ctx, "event_bounds")
+	defer finish(&err)
+
+	return r.Posts.EventBounds(ctx)
+}
+
+func (r *PostRepo) EventSnapshot(ctx context.Context) (xmin, xmax int64, err error) {
+	ctx, finish := r.start(ctx, "event_snapshot")
+	defer finish(&err)
+
+	return r.Posts.EventSnapshot(ctx)
+}
+
+func (r *PostRepo) PurgeEvents(ctx context.Context, before time.Time, limit int) (_ int, err error) {
+	ctx, finish := r.start(ctx, "purge_events")
+	defer finish(&err)
+
+	return r.Posts.PurgeEvents(ctx, before, limit)
+}
+
+// start opens the span of a call, the returned func ends it and records
+// the duration of the call along with its outcome.
+func (r *PostRepo) start(ctx context.Context, method string) (context.Context, func(*error)) {
+	ctx, span := tracer.Start(ctx, "posts."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperation(method),
+			semconv.DBSQLTable("posts"),
+		),
+	)
+	start := time.Now()
+
+	return ctx, func(err *error) {
+		outcome := "ok"
+		switch {
+		case errors.Is(*err, pkgerrors.ErrNotFound):
+			outcome = "not_found"
+		case *err != nil:
+			outcome = "error"
+			span.RecordError(*err)
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+
+		metrics.ObserveQuery("posts", method, outcome, time.Since(start))
+		span.End()
+	}
+}
@@ -0,0 +1,83 @@
+package instrumented_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/metrics"
+	"github.com/kokhno-nikolay/news/internal/repository/instrumented"
+	mock_repository "github.com/kokhno-nikolay/news/internal/repository/mocks"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+func TestPostRepo_Observe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	posts := mock_repository.NewMockPosts(ctrl)
+	repo := instrumented.NewPostRepo(posts)
+	ctx := context.Background()
+
+	posts.EXPECT().Get(gomock.Any(), 1).Return(&domain.Post{ID: 1}, nil)
+	posts.EXPECT().Get(gomock.Any(), 2).Return(nil, errors.ErrNotFound)
+	posts.EXPECT().EventBounds(gomock.Any()).Return(int64(1), int64(5), nil)
+
+	post, err := repo.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, post.ID)
+
+	_, err = repo.Get(ctx, 2)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	first, last, err := repo.EventBounds(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), first)
+	assert.Equal(t, int64(5), last)
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, `repository_query_duration_seconds_count{method="get",outcome="ok",repository="posts"} 1`)
+	assert.Contains(t, body, `repository_query_duration_seconds_count{method="get",outcome="not_found",repository="posts"} 1`)
+	assert.Contains(t, body, `repository_query_duration_seconds_count{method="event_bounds",outcome="ok",repository="posts"} 1`)
+}
+
+func TestPostRepo_Trace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(trace.NewNoopTracerProvider()) })
+
+	ctrl := gomock.NewController(t)
+	posts := mock_repository.NewMockPosts(ctrl)
+	repo := instrumented.NewPostRepo(posts)
+	ctx := context.Background()
+
+	posts.EXPECT().Delete(gomock.Any(), 1).DoAndReturn(func(ctx context.Context, id int) (bool, error) {
+		assert.True(t, trace.SpanContextFromContext(ctx).IsValid())
+		return false, assert.AnError
+	})
+	posts.EXPECT().Get(gomock.Any(), 2).Return(nil, errors.ErrNotFound)
+
+	_, err := repo.Delete(ctx, 1)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	_, err = repo.Get(ctx, 2)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 2) {
+		assert.Equal(t, "posts.delete", spans[0].Name())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, "posts.get", spans[1].Name())
+		assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	}
+}
@@ -6,7 +6,9 @@ package mock_repository
 
 import (
 	context "context"
+	json "encoding/json"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	domain "github.com/kokhno-nikolay/news/domain"
@@ -35,6 +37,21 @@ func (m *MockPosts) EXPECT() *MockPostsMockRecorder {
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockPosts) Count(ctx context.Context, filter *domain.PostFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockPostsMockRecorder) Count(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockPosts)(nil).Count), ctx, filter)
+}
+
 // Create mocks base method.
 func (m *MockPosts) Create(ctx context.Context, input *domain.PostInput) (*domain.Post, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +67,21 @@ func (mr *MockPostsMockRecorder) Create(ctx, input interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPosts)(nil).Create), ctx, input)
 }
 
+// CreateBatch mocks base method.
+func (m *MockPosts) CreateBatch(ctx context.Context, inputs []*domain.PostInput) ([]*domain.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, inputs)
+	ret0, _ := ret[0].([]*domain.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockPostsMockRecorder) CreateBatch(ctx, inputs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockPosts)(nil).CreateBatch), ctx, inputs)
+}
+
 // Delete mocks base method.
 func (m *MockPosts) Delete(ctx context.Context, id int) (bool, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +97,53 @@ func (mr *MockPostsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPosts)(nil).Delete), ctx, id)
 }
 
+// DeleteBatch mocks base method.
+func (m *MockPosts) DeleteBatch(ctx context.Context, ids []int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBatch", ctx, ids)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBatch indicates an expected call of DeleteBatch.
+func (mr *MockPostsMockRecorder) DeleteBatch(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBatch", reflect.TypeOf((*MockPosts)(nil).DeleteBatch), ctx, ids)
+}
+
+// EventBounds mocks base method.
+func (m *MockPosts) EventBounds(ctx context.Context) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventBounds", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EventBounds indicates an expected call of EventBounds.
+func (mr *MockPostsMockRecorder) EventBounds(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventBounds", reflect.TypeOf((*MockPosts)(nil).EventBounds), ctx)
+}
+
+// EventSnapshot mocks base method.
+func (m *MockPosts) EventSnapshot(ctx context.Context) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventSnapshot", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EventSnapshot indicates an expected call of EventSnapshot.
+func (mr *MockPostsMockRecorder) EventSnapshot(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventSnapshot", reflect.TypeOf((*MockPosts)(nil).EventSnapshot), ctx)
+}
+
 // Get mocks base method.
 func (m *MockPosts) Get(ctx context.Context, id int) (*domain.Post, error) {
 	m.ctrl.T.Helper()
@@ -80,19 +159,214 @@ func (mr *MockPostsMockRecorder) Get(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPosts)(nil).Get), ctx, id)
 }
 
+// GetBatch mocks base method.
+func (m *MockPosts) GetBatch(ctx context.Context, ids []int) ([]*domain.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, ids)
+	ret0, _ := ret[0].([]*domain.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockPostsMockRecorder) GetBatch(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockPosts)(nil).GetBatch), ctx, ids)
+}
+
+// GetRevision mocks base method.
+func (m *MockPosts) GetRevision(ctx context.Context, postID, revision int) (*domain.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, postID, revision)
+	ret0, _ := ret[0].(*domain.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockPostsMockRecorder) GetRevision(ctx, postID, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockPosts)(nil).GetRevision), ctx, postID, revision)
+}
+
+// GetTrashed mocks base method.
+func (m *MockPosts) GetTrashed(ctx context.Context, id int) (*domain.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrashed", ctx, id)
+	ret0, _ := ret[0].(*domain.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrashed indicates an expected call of GetTrashed.
+func (mr *MockPostsMockRecorder) GetTrashed(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrashed", reflect.TypeOf((*MockPosts)(nil).GetTrashed), ctx, id)
+}
+
 // List mocks base method.
-func (m *MockPosts) List(ctx context.Context, limit *int) ([]*domain.Post, error) {
+func (m *MockPosts) List(ctx context.Context, params *domain.PostListParams) ([]*domain.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx, limit)
+	ret := m.ctrl.Call(m, "List", ctx, params)
 	ret0, _ := ret[0].([]*domain.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockPostsMockRecorder) List(ctx, limit interface{}) *gomock.Call {
+func (mr *MockPostsMockRecorder) List(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPosts)(nil).List), ctx, params)
+}
+
+// ListEvents mocks base method.
+func (m *MockPosts) ListEvents(ctx context.Context, afterID int64, limit int) ([]*domain.PostEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", ctx, afterID, limit)
+	ret0, _ := ret[0].([]*domain.PostEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockPostsMockRecorder) ListEvents(ctx, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockPosts)(nil).ListEvents), ctx, afterID, limit)
+}
+
+// ListRevisions mocks base method.
+func (m *MockPosts) ListRevisions(ctx context.Context, postID, limit, offset int) ([]*domain.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, postID, limit, offset)
+	ret0, _ := ret[0].([]*domain.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockPostsMockRecorder) ListRevisions(ctx, postID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockPosts)(nil).ListRevisions), ctx, postID, limit, offset)
+}
+
+// ListTrash mocks base method.
+func (m *MockPosts) ListTrash(ctx context.Context, authorID, limit, offset int) ([]*domain.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx, authorID, limit, offset)
+	ret0, _ := ret[0].([]*domain.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockPostsMockRecorder) ListTrash(ctx, authorID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockPosts)(nil).ListTrash), ctx, authorID, limit, offset)
+}
+
+// PublishDue mocks base method.
+func (m *MockPosts) PublishDue(ctx context.Context, now time.Time, limit int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDue", ctx, now, limit)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDue indicates an expected call of PublishDue.
+func (mr *MockPostsMockRecorder) PublishDue(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDue", reflect.TypeOf((*MockPosts)(nil).PublishDue), ctx, now, limit)
+}
+
+// Purge mocks base method.
+func (m *MockPosts) Purge(ctx context.Context, id int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockPostsMockRecorder) Purge(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockPosts)(nil).Purge), ctx, id)
+}
+
+// PurgeEvents mocks base method.
+func (m *MockPosts) PurgeEvents(ctx context.Context, before time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeEvents", ctx, before, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeEvents indicates an expected call of PurgeEvents.
+func (mr *MockPostsMockRecorder) PurgeEvents(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeEvents", reflect.TypeOf((*MockPosts)(nil).PurgeEvents), ctx, before, limit)
+}
+
+// PurgeTrashed mocks base method.
+func (m *MockPosts) PurgeTrashed(ctx context.Context, before time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrashed", ctx, before, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrashed indicates an expected call of PurgeTrashed.
+func (mr *MockPostsMockRecorder) PurgeTrashed(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrashed", reflect.TypeOf((*MockPosts)(nil).PurgeTrashed), ctx, before, limit)
+}
+
+// Restore mocks base method.
+func (m *MockPosts) Restore(ctx context.Context, id int) (*domain.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(*domain.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockPostsMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockPosts)(nil).Restore), ctx, id)
+}
+
+// Search mocks base method.
+func (m *MockPosts) Search(ctx context.Context, params *domain.SearchParams) ([]*domain.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, params)
+	ret0, _ := ret[0].([]*domain.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockPostsMockRecorder) Search(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockPosts)(nil).Search), ctx, params)
+}
+
+// SetPublishAt mocks base method.
+func (m *MockPosts) SetPublishAt(ctx context.Context, id int, status domain.PostStatus, publishAt *time.Time) (*domain.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPublishAt", ctx, id, status, publishAt)
+	ret0, _ := ret[0].(*domain.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPublishAt indicates an expected call of SetPublishAt.
+func (mr *MockPostsMockRecorder) SetPublishAt(ctx, id, status, publishAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPosts)(nil).List), ctx, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPublishAt", reflect.TypeOf((*MockPosts)(nil).SetPublishAt), ctx, id, status, publishAt)
 }
 
 // Update mocks base method.
@@ -109,3 +383,616 @@ func (mr *MockPostsMockRecorder) Update(ctx, id, input interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPosts)(nil).Update), ctx, id, input)
 }
+
+// UpdateBatch mocks base method.
+func (m *MockPosts) UpdateBatch(ctx context.Context, updates []*domain.PostBatchUpdate) ([]*domain.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBatch", ctx, updates)
+	ret0, _ := ret[0].([]*domain.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBatch indicates an expected call of UpdateBatch.
+func (mr *MockPostsMockRecorder) UpdateBatch(ctx, updates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBatch", reflect.TypeOf((*MockPosts)(nil).UpdateBatch), ctx, updates)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockPosts) UpdateStatus(ctx context.Context, id int, from, to domain.PostStatus) (*domain.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, from, to)
+	ret0, _ := ret[0].(*domain.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockPostsMockRecorder) UpdateStatus(ctx, id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockPosts)(nil).UpdateStatus), ctx, id, from, to)
+}
+
+// MockOutbox is a mock of Outbox interface.
+type MockOutbox struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxMockRecorder
+}
+
+// MockOutboxMockRecorder is the mock recorder for MockOutbox.
+type MockOutboxMockRecorder struct {
+	mock *MockOutbox
+}
+
+// NewMockOutbox creates a new mock instance.
+func NewMockOutbox(ctrl *gomock.Controller) *MockOutbox {
+	mock := &MockOutbox{ctrl: ctrl}
+	mock.recorder = &MockOutboxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutbox) EXPECT() *MockOutboxMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockOutbox) Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.PostEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, limit, lease)
+	ret0, _ := ret[0].([]*domain.PostEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockOutboxMockRecorder) Claim(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockOutbox)(nil).Claim), ctx, limit, lease)
+}
+
+// MarkDelivered mocks base method.
+func (m *MockOutbox) MarkDelivered(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDelivered", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDelivered indicates an expected call of MarkDelivered.
+func (mr *MockOutboxMockRecorder) MarkDelivered(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDelivered", reflect.TypeOf((*MockOutbox)(nil).MarkDelivered), ctx, id)
+}
+
+// MarkFailed mocks base method.
+func (m *MockOutbox) MarkFailed(ctx context.Context, id int64, reason string, retryAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, id, reason, retryAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockOutboxMockRecorder) MarkFailed(ctx, id, reason, retryAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockOutbox)(nil).MarkFailed), ctx, id, reason, retryAt)
+}
+
+// MockWebhooks is a mock of Webhooks interface.
+type MockWebhooks struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhooksMockRecorder
+}
+
+// MockWebhooksMockRecorder is the mock recorder for MockWebhooks.
+type MockWebhooksMockRecorder struct {
+	mock *MockWebhooks
+}
+
+// NewMockWebhooks creates a new mock instance.
+func NewMockWebhooks(ctrl *gomock.Controller) *MockWebhooks {
+	mock := &MockWebhooks{ctrl: ctrl}
+	mock.recorder = &MockWebhooksMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhooks) EXPECT() *MockWebhooksMockRecorder {
+	return m.recorder
+}
+
+// ClaimDeliveries mocks base method.
+func (m *MockWebhooks) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]*domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeliveries indicates an expected call of ClaimDeliveries.
+func (mr *MockWebhooksMockRecorder) ClaimDeliveries(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeliveries", reflect.TypeOf((*MockWebhooks)(nil).ClaimDeliveries), ctx, limit, lease)
+}
+
+// Create mocks base method.
+func (m *MockWebhooks) Create(ctx context.Context, input *domain.WebhookInput) (*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, input)
+	ret0, _ := ret[0].(*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhooksMockRecorder) Create(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhooks)(nil).Create), ctx, input)
+}
+
+// CreateDelivery mocks base method.
+func (m *MockWebhooks) CreateDelivery(ctx context.Context, subscriptionID int, event domain.WebhookEvent, payload json.RawMessage, lease time.Duration) (*domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDelivery", ctx, subscriptionID, event, payload, lease)
+	ret0, _ := ret[0].(*domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDelivery indicates an expected call of CreateDelivery.
+func (mr *MockWebhooksMockRecorder) CreateDelivery(ctx, subscriptionID, event, payload, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDelivery", reflect.TypeOf((*MockWebhooks)(nil).CreateDelivery), ctx, subscriptionID, event, payload, lease)
+}
+
+// Delete mocks base method.
+func (m *MockWebhooks) Delete(ctx context.Context, id int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebhooksMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhooks)(nil).Delete), ctx, id)
+}
+
+// Enqueue mocks base method.
+func (m *MockWebhooks) Enqueue(ctx context.Context, eventID int64, event domain.WebhookEvent, payload json.RawMessage) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, eventID, event, payload)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockWebhooksMockRecorder) Enqueue(ctx, eventID, event, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockWebhooks)(nil).Enqueue), ctx, eventID, event, payload)
+}
+
+// Get mocks base method.
+func (m *MockWebhooks) Get(ctx context.Context, id int) (*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWebhooksMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWebhooks)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockWebhooks) List(ctx context.Context, limit, offset int) ([]*domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, limit, offset)
+	ret0, _ := ret[0].([]*domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockWebhooksMockRecorder) List(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhooks)(nil).List), ctx, limit, offset)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhooks) ListDeliveries(ctx context.Context, subscriptionID, limit, offset int) ([]*domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, subscriptionID, limit, offset)
+	ret0, _ := ret[0].([]*domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhooksMockRecorder) ListDeliveries(ctx, subscriptionID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhooks)(nil).ListDeliveries), ctx, subscriptionID, limit, offset)
+}
+
+// MarkDelivered mocks base method.
+func (m *MockWebhooks) MarkDelivered(ctx context.Context, id int64, responseStatus int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDelivered", ctx, id, responseStatus)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDelivered indicates an expected call of MarkDelivered.
+func (mr *MockWebhooksMockRecorder) MarkDelivered(ctx, id, responseStatus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDelivered", reflect.TypeOf((*MockWebhooks)(nil).MarkDelivered), ctx, id, responseStatus)
+}
+
+// MarkFailed mocks base method.
+func (m *MockWebhooks) MarkFailed(ctx context.Context, id int64, responseStatus int, reason string, retryAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, id, responseStatus, reason, retryAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockWebhooksMockRecorder) MarkFailed(ctx, id, responseStatus, reason, retryAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockWebhooks)(nil).MarkFailed), ctx, id, responseStatus, reason, retryAt)
+}
+
+// PurgeDeliveries mocks base method.
+func (m *MockWebhooks) PurgeDeliveries(ctx context.Context, before time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeliveries", ctx, before, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeliveries indicates an expected call of PurgeDeliveries.
+func (mr *MockWebhooksMockRecorder) PurgeDeliveries(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeliveries", reflect.TypeOf((*MockWebhooks)(nil).PurgeDeliveries), ctx, before, limit)
+}
+
+// MockPostNotifier is a mock of PostNotifier interface.
+type MockPostNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockPostNotifierMockRecorder
+}
+
+// MockPostNotifierMockRecorder is the mock recorder for MockPostNotifier.
+type MockPostNotifierMockRecorder struct {
+	mock *MockPostNotifier
+}
+
+// NewMockPostNotifier creates a new mock instance.
+func NewMockPostNotifier(ctrl *gomock.Controller) *MockPostNotifier {
+	mock := &MockPostNotifier{ctrl: ctrl}
+	mock.recorder = &MockPostNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPostNotifier) EXPECT() *MockPostNotifierMockRecorder {
+	return m.recorder
+}
+
+// Changed mocks base method.
+func (m *MockPostNotifier) Changed() <-chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Changed")
+	ret0, _ := ret[0].(<-chan struct{})
+	return ret0
+}
+
+// Changed indicates an expected call of Changed.
+func (mr *MockPostNotifierMockRecorder) Changed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Changed", reflect.TypeOf((*MockPostNotifier)(nil).Changed))
+}
+
+// MockCategories is a mock of Categories interface.
+type MockCategories struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoriesMockRecorder
+}
+
+// MockCategoriesMockRecorder is the mock recorder for MockCategories.
+type MockCategoriesMockRecorder struct {
+	mock *MockCategories
+}
+
+// NewMockCategories creates a new mock instance.
+func NewMockCategories(ctrl *gomock.Controller) *MockCategories {
+	mock := &MockCategories{ctrl: ctrl}
+	mock.recorder = &MockCategoriesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategories) EXPECT() *MockCategoriesMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCategories) Create(ctx context.Context, input *domain.CategoryInput) (*domain.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, input)
+	ret0, _ := ret[0].(*domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCategoriesMockRecorder) Create(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCategories)(nil).Create), ctx, input)
+}
+
+// Delete mocks base method.
+func (m *MockCategories) Delete(ctx context.Context, id int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCategoriesMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCategories)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockCategories) Get(ctx context.Context, id int) (*domain.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCategoriesMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCategories)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockCategories) List(ctx context.Context) ([]*domain.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]*domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCategoriesMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCategories)(nil).List), ctx)
+}
+
+// Update mocks base method.
+func (m *MockCategories) Update(ctx context.Context, id int, input *domain.CategoryInput) (*domain.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, input)
+	ret0, _ := ret[0].(*domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCategoriesMockRecorder) Update(ctx, id, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCategories)(nil).Update), ctx, id, input)
+}
+
+// MockTags is a mock of Tags interface.
+type MockTags struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagsMockRecorder
+}
+
+// MockTagsMockRecorder is the mock recorder for MockTags.
+type MockTagsMockRecorder struct {
+	mock *MockTags
+}
+
+// NewMockTags creates a new mock instance.
+func NewMockTags(ctrl *gomock.Controller) *MockTags {
+	mock := &MockTags{ctrl: ctrl}
+	mock.recorder = &MockTagsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTags) EXPECT() *MockTagsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTags) Create(ctx context.Context, input *domain.TagInput) (*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, input)
+	ret0, _ := ret[0].(*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTagsMockRecorder) Create(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTags)(nil).Create), ctx, input)
+}
+
+// Delete mocks base method.
+func (m *MockTags) Delete(ctx context.Context, id int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTags)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockTags) Get(ctx context.Context, id int) (*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTagsMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTags)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockTags) List(ctx context.Context, limit, offset int) ([]*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, limit, offset)
+	ret0, _ := ret[0].([]*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTagsMockRecorder) List(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTags)(nil).List), ctx, limit, offset)
+}
+
+// Update mocks base method.
+func (m *MockTags) Update(ctx context.Context, id int, input *domain.TagInput) (*domain.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, input)
+	ret0, _ := ret[0].(*domain.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTagsMockRecorder) Update(ctx, id, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTags)(nil).Update), ctx, id, input)
+}
+
+// MockAuthors is a mock of Authors interface.
+type MockAuthors struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorsMockRecorder
+}
+
+// MockAuthorsMockRecorder is the mock recorder for MockAuthors.
+type MockAuthorsMockRecorder struct {
+	mock *MockAuthors
+}
+
+// NewMockAuthors creates a new mock instance.
+func NewMockAuthors(ctrl *gomock.Controller) *MockAuthors {
+	mock := &MockAuthors{ctrl: ctrl}
+	mock.recorder = &MockAuthorsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthors) EXPECT() *MockAuthorsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuthors) Create(ctx context.Context, input *domain.AuthorInput) (*domain.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, input)
+	ret0, _ := ret[0].(*domain.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuthorsMockRecorder) Create(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuthors)(nil).Create), ctx, input)
+}
+
+// Delete mocks base method.
+func (m *MockAuthors) Delete(ctx context.Context, id int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAuthorsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuthors)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockAuthors) Get(ctx context.Context, id int) (*domain.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*domain.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAuthorsMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAuthors)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockAuthors) List(ctx context.Context, limit, offset int) ([]*domain.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, limit, offset)
+	ret0, _ := ret[0].([]*domain.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAuthorsMockRecorder) List(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAuthors)(nil).List), ctx, limit, offset)
+}
+
+// Update mocks base method.
+func (m *MockAuthors) Update(ctx context.Context, id int, input *domain.AuthorInput) (*domain.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, input)
+	ret0, _ := ret[0].(*domain.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAuthorsMockRecorder) Update(ctx, id, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuthors)(nil).Update), ctx, id, input)
+}
@@ -0,0 +1,212 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+type AuthorRepo struct {
+	db *sqlx.DB
+}
+
+func NewAuthorRepo(db *sqlx.DB) *AuthorRepo {
+	return &AuthorRepo{
+		db: db,
+	}
+}
+
+func (r *AuthorRepo) Get(ctx context.Context, id int) (*domain.Author, error) {
+	query := `
+		SELECT id, name, email, bio, created_at, updated_at
+		FROM authors
+		WHERE id = $1
+	`
+
+	var author domain.Author
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&author.ID,
+		&author.Name,
+		&author.Email,
+		&author.Bio,
+		&author.CreatedAt,
+		&author.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &author, nil
+}
+
+func (r *AuthorRepo) List(ctx context.Context, limit, offset int) ([]*domain.Author, error) {
+	query := `
+		SELECT id, name, email, bio, created_at, updated_at
+		FROM authors
+		ORDER BY name, id
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, getQueryLimit(limit), offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var authors []*domain.Author
+
+	for rows.Next() {
+		var author domain.Author
+		err := rows.Scan(
+			&author.ID,
+			&author.Name,
+			&author.Email,
+			&author.Bio,
+			&author.CreatedAt,
+			&author.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		authors = append(authors, &author)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return authors, nil
+}
+
+func (r *AuthorRepo) Create(ctx context.Context, input *domain.AuthorInput) (*domain.Author, error) {
+	query := `
+		INSERT INTO authors (name, email, bio)
+		VALUES ($1, $2, $3)
+		RETURNING id, name, email, bio, created_at, updated_at
+	`
+
+	var author domain.Author
+	err := r.db.QueryRowContext(ctx, query, input.Name, input.Email, input.Bio).Scan(
+		&author.ID,
+		&author.Name,
+		&author.Email,
+		&author.Bio,
+		&author.CreatedAt,
+		&author.UpdatedAt,
+	)
+	if err != nil {
+		if isPgError(err, uniqueViolation) {
+			return nil, errors.ErrAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return &author, nil
+}
+
+func (r *AuthorRepo) Update(ctx context.Context, id int, input *domain.AuthorInput) (*domain.Author, error) {
+	query := `
+		UPDATE authors
+		SET name = $1, email = $2, bio = $3
+		WHERE id = $4
+		RETURNING id, name, email, bio, created_at, updated_at
+	`
+
+	var author domain.Author
+	err := r.db.QueryRowContext(ctx, query, input.Name, input.Email, input.Bio, id).Scan(
+		&author.ID,
+		&author.Name,
+		&author.Email,
+		&author.Bio,
+		&author.CreatedAt,
+		&author.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+
+		if isPgError(err, uniqueViolation) {
+			return nil, errors.ErrAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return &author, nil
+}
+
+func (r *AuthorRepo) Delete(ctx context.Context, id int) (bool, error) {
+	query := `
+		DELETE FROM authors
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// loadPostAuthors fills the author of the given posts. Emails are left out,
+// posts are public while author contacts are not.
+func loadPostAuthors(ctx context.Context, q sqlx.QueryerContext, posts []*domain.Post) error {
+	var ids []int64
+	byAuthor := make(map[int][]*domain.Post)
+	for _, post := range posts {
+		if post.AuthorID == 0 {
+			continue
+		}
+
+		if _, ok := byAuthor[post.AuthorID]; !ok {
+			ids = append(ids, int64(post.AuthorID))
+		}
+		byAuthor[post.AuthorID] = append(byAuthor[post.AuthorID], post)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+		SELECT id, name, bio, created_at, updated_at
+		FROM authors
+		WHERE id = ANY($1)
+	`
+
+	rows, err := q.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var author domain.Author
+		if err := rows.Scan(&author.ID, &author.Name, &author.Bio, &author.CreatedAt, &author.UpdatedAt); err != nil {
+			return err
+		}
+
+		for _, post := range byAuthor[author.ID] {
+			post.Author = &author
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,107 @@
+package postgresql_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/repository/postgresql"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+func TestAuthorRepo_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewAuthorRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	input := &domain.AuthorInput{
+		Name:  "Test Author",
+		Email: "author@example.com",
+		Bio:   "Test Bio",
+	}
+	now := time.Now()
+
+	mock.ExpectQuery("INSERT INTO authors").
+		WithArgs(input.Name, input.Email, input.Bio).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "bio", "created_at", "updated_at"}).
+			AddRow(1, input.Name, input.Email, input.Bio, now, now))
+
+	author, err := repo.Create(context.Background(), input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, author.ID)
+	assert.Equal(t, input.Email, author.Email)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAuthorRepo_Update_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewAuthorRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery("UPDATE authors SET name = \\$1, email = \\$2, bio = \\$3 WHERE id = \\$4").
+		WithArgs("Test Author", "author@example.com", "", 5).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.Update(context.Background(), 5, &domain.AuthorInput{
+		Name:  "Test Author",
+		Email: "author@example.com",
+	})
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_Get_WithAuthor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM posts WHERE id = \\$1").
+		WithArgs(1).
+		WillReturnRows(newPostRows().
+			AddRow(1, "Test Title", "Test Content", 4, "published", now, nil, now, now, nil, 1))
+	mock.ExpectQuery("SELECT id, name, bio, created_at, updated_at FROM authors WHERE id = ANY\\(\\$1\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "bio", "created_at", "updated_at"}).
+			AddRow(4, "Test Author", "Test Bio", now, now))
+	expectPostTaxonomy(mock)
+
+	post, err := repo.Get(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, post.AuthorID)
+	assert.NotNil(t, post.Author)
+	assert.Equal(t, "Test Author", post.Author.Name)
+	assert.Empty(t, post.Author.Email)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
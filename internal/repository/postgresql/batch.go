@@ -0,0 +1,220 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+// GetBatch returns the posts with the given ids that are not in the trash,
+// ordered by id. Missing posts are left out.
+func (r *PostRepo) GetBatch(ctx context.Context, ids []int) ([]*domain.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE id = ANY($1) AND deleted_at IS NULL
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	postList, err := scanPosts(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadPostRelations(ctx, r.db, postList); err != nil {
+		return nil, err
+	}
+
+	return postList, nil
+}
+
+// CreateBatch inserts the posts with a single multi-row INSERT in one
+// transaction, the returned posts follow the order of the inputs. A link to
+// a missing category fails with a *domain.BatchItemError.
+func (r *PostRepo) CreateBatch(ctx context.Context, inputs []*domain.PostInput) ([]*domain.Post, error) {
+	if len(inputs) == 0 {
+		return nil, nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	values := make([]string, len(inputs))
+	args := make([]interface{}, 0, 4*len(inputs))
+	for i, input := range inputs {
+		args = append(args, input.Title, input.Content, input.AuthorID, input.SearchLanguage)
+		values[i] = fmt.Sprintf("($%d, $%d, NULLIF($%d, 0), $%d)", len(args)-3, len(args)-2, len(args)-1, len(args))
+	}
+
+	query := `
+		INSERT INTO posts (title, content, author_id, search_language)
+		VALUES ` + strings.Join(values, ", ") + `
+		RETURNING ` + postColumns + `
+	`
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	var postList []*domain.Post
+	if err == nil {
+		postList, err = scanPosts(rows)
+	}
+	if err != nil {
+		if isPgError(err, foreignKeyViolation) {
+			return nil, fmt.Errorf("author: %w", errors.ErrNotFound)
+		}
+
+		return nil, err
+	}
+
+	if len(postList) != len(inputs) {
+		return nil, fmt.Errorf("inserted %d posts out of %d", len(postList), len(inputs))
+	}
+
+	// ids are drawn in the order of the values list, sorting by id lines the
+	// returned rows up with the inputs
+	sort.Slice(postList, func(i, j int) bool { return postList[i].ID < postList[j].ID })
+
+	if err := insertFirstPostRevisions(ctx, tx, postList, inputs); err != nil {
+		return nil, err
+	}
+
+	for i, input := range inputs {
+		if input.CategoryIDs == nil && input.Tags == nil {
+			continue
+		}
+
+		if err := setPostTaxonomy(ctx, tx, postList[i].ID, input); err != nil {
+			return nil, &domain.BatchItemError{Index: i, Err: err}
+		}
+	}
+
+	if err := loadPostRelations(ctx, tx, postList); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return postList, nil
+}
+
+// UpdateBatch applies the updates in one transaction, the first failing
+// update rolls back all of them and is returned as a *domain.BatchItemError.
+func (r *PostRepo) UpdateBatch(ctx context.Context, updates []*domain.PostBatchUpdate) ([]*domain.Post, error) {
+	if len(updates) == 0 {
+		return nil, nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	postList := make([]*domain.Post, len(updates))
+	for i, update := range updates {
+		post, err := updatePostTx(ctx, tx, update.ID, &update.Input)
+		if err != nil {
+			return nil, &domain.BatchItemError{Index: i, Err: err}
+		}
+		postList[i] = post
+	}
+
+	if err := loadPostRelations(ctx, tx, postList); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return postList, nil
+}
+
+// DeleteBatch moves the posts to the trash and returns the ids of the posts
+// that were actually moved.
+func (r *PostRepo) DeleteBatch(ctx context.Context, ids []int) ([]int, error) {
+	query := `
+		UPDATE posts
+		SET deleted_at = NOW()
+		WHERE id = ANY($1) AND deleted_at IS NULL
+		RETURNING id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deleted []int
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		deleted = append(deleted, id)
+	}
+
+	return deleted, rows.Err()
+}
+
+// insertFirstPostRevisions records the first revision of freshly created
+// posts.
+func insertFirstPostRevisions(ctx context.Context, tx *sqlx.Tx, posts []*domain.Post, inputs []*domain.PostInput) error {
+	ids := make([]int64, len(posts))
+	editedBy := make([]int64, len(posts))
+	for i, post := range posts {
+		ids[i] = int64(post.ID)
+		editedBy[i] = int64(inputs[i].EditedBy)
+	}
+
+	query := `
+		INSERT INTO post_revisions (post_id, revision, title, content, edited_by)
+		SELECT p.id, 1, p.title, p.content, NULLIF(e.edited_by, 0)
+		FROM unnest($1::int[], $2::int[]) AS e(post_id, edited_by)
+		JOIN posts p ON p.id = e.post_id
+	`
+
+	_, err := tx.ExecContext(ctx, query, pq.Array(ids), pq.Array(editedBy))
+
+	return err
+}
+
+// scanPosts reads all rows of a post query and closes them.
+func scanPosts(rows *sql.Rows) ([]*domain.Post, error) {
+	defer rows.Close()
+
+	var postList []*domain.Post
+
+	for rows.Next() {
+		var post domain.Post
+		if err := scanPost(rows, &post); err != nil {
+			return nil, err
+		}
+		postList = append(postList, &post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return postList, nil
+}
@@ -0,0 +1,151 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/repository/postgresql"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+func TestPostRepo_GetBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT " + postColumns + " FROM posts WHERE id = ANY\\(\\$1\\) AND deleted_at IS NULL ORDER BY id").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(newPostRows().
+			AddRow(1, "First", "First Content", 0, "published", now, nil, now, now, nil, 1).
+			AddRow(3, "Third", "Third Content", 0, "draft", nil, nil, now, now, nil, 1))
+
+	expectPostTaxonomy(mock)
+
+	posts, err := repo.GetBatch(context.Background(), []int{1, 2, 3})
+
+	assert.NoError(t, err)
+	assert.Len(t, posts, 2)
+	assert.Equal(t, 3, posts[1].ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_CreateBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	inputs := []*domain.PostInput{
+		{Title: "First", Content: "First Content", SearchLanguage: "english"},
+		{Title: "Second", Content: "Second Content", SearchLanguage: "simple"},
+	}
+	now := time.Now()
+
+	mock.ExpectBegin()
+	// the rows come back in any order, the result follows the inputs
+	mock.ExpectQuery("INSERT INTO posts \\(title, content, author_id, search_language\\) VALUES \\(\\$1, \\$2, NULLIF\\(\\$3, 0\\), \\$4\\), \\(\\$5, \\$6, NULLIF\\(\\$7, 0\\), \\$8\\) RETURNING "+postColumns).
+		WithArgs("First", "First Content", 0, "english", "Second", "Second Content", 0, "simple").
+		WillReturnRows(newPostRows().
+			AddRow(8, "Second", "Second Content", 0, "draft", nil, nil, now, now, nil, 1).
+			AddRow(7, "First", "First Content", 0, "draft", nil, nil, now, now, nil, 1))
+	mock.ExpectExec("INSERT INTO post_revisions \\(post_id, revision, title, content, edited_by\\) SELECT p.id, 1").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	expectPostTaxonomy(mock)
+	mock.ExpectCommit()
+
+	posts, err := repo.CreateBatch(context.Background(), inputs)
+
+	assert.NoError(t, err)
+	assert.Len(t, posts, 2)
+	assert.Equal(t, 7, posts[0].ID)
+	assert.Equal(t, "First", posts[0].Title)
+	assert.Equal(t, 8, posts[1].ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_UpdateBatch_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	updates := []*domain.PostBatchUpdate{
+		{ID: 1, Input: domain.PostInput{Title: "First", Content: "First Content", Version: 1}},
+		{ID: 2, Input: domain.PostInput{Title: "Second", Content: "Second Content", Version: 1}},
+	}
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE posts SET title = \\$1, content = \\$2 WHERE id = \\$3 AND deleted_at IS NULL AND version = \\$4 RETURNING "+postColumns).
+		WithArgs("First", "First Content", 1, 1).
+		WillReturnRows(newPostRows().
+			AddRow(1, "First", "First Content", 0, "draft", nil, nil, now, now, nil, 2))
+	expectPostRevision(mock, 1)
+	mock.ExpectQuery("UPDATE posts SET title = \\$1, content = \\$2 WHERE id = \\$3 AND deleted_at IS NULL AND version = \\$4 RETURNING "+postColumns).
+		WithArgs("Second", "Second Content", 2, 1).
+		WillReturnRows(newPostRows())
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	posts, err := repo.UpdateBatch(context.Background(), updates)
+
+	var itemErr *domain.BatchItemError
+	assert.Nil(t, posts)
+	assert.ErrorAs(t, err, &itemErr)
+	assert.Equal(t, 1, itemErr.Index)
+	assert.ErrorIs(t, err, errors.ErrVersionConflict)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_DeleteBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery("UPDATE posts SET deleted_at = NOW\\(\\) WHERE id = ANY\\(\\$1\\) AND deleted_at IS NULL RETURNING id").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(3))
+
+	deleted, err := repo.DeleteBatch(context.Background(), []int{1, 2, 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, deleted)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,138 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+type CategoryRepo struct {
+	db *sqlx.DB
+}
+
+func NewCategoryRepo(db *sqlx.DB) *CategoryRepo {
+	return &CategoryRepo{
+		db: db,
+	}
+}
+
+func (r *CategoryRepo) Get(ctx context.Context, id int) (*domain.Category, error) {
+	query := `
+		SELECT id, name, slug, created_at
+		FROM categories
+		WHERE id = $1
+	`
+
+	var category domain.Category
+	err := r.db.QueryRowContext(ctx, query, id).
+		Scan(&category.ID, &category.Name, &category.Slug, &category.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (r *CategoryRepo) List(ctx context.Context) ([]*domain.Category, error) {
+	query := `
+		SELECT id, name, slug, created_at
+		FROM categories
+		ORDER BY name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*domain.Category
+
+	for rows.Next() {
+		var category domain.Category
+		if err := rows.Scan(&category.ID, &category.Name, &category.Slug, &category.CreatedAt); err != nil {
+			return nil, err
+		}
+		categories = append(categories, &category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *CategoryRepo) Create(ctx context.Context, input *domain.CategoryInput) (*domain.Category, error) {
+	query := `
+		INSERT INTO categories (name, slug)
+		VALUES ($1, $2)
+		RETURNING id, name, slug, created_at
+	`
+
+	var category domain.Category
+	err := r.db.QueryRowContext(ctx, query, input.Name, input.Slug).
+		Scan(&category.ID, &category.Name, &category.Slug, &category.CreatedAt)
+	if err != nil {
+		if isPgError(err, uniqueViolation) {
+			return nil, errors.ErrAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (r *CategoryRepo) Update(ctx context.Context, id int, input *domain.CategoryInput) (*domain.Category, error) {
+	query := `
+		UPDATE categories
+		SET name = $1, slug = $2
+		WHERE id = $3
+		RETURNING id, name, slug, created_at
+	`
+
+	var category domain.Category
+	err := r.db.QueryRowContext(ctx, query, input.Name, input.Slug, id).
+		Scan(&category.ID, &category.Name, &category.Slug, &category.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+
+		if isPgError(err, uniqueViolation) {
+			return nil, errors.ErrAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (r *CategoryRepo) Delete(ctx context.Context, id int) (bool, error) {
+	query := `
+		DELETE FROM categories
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
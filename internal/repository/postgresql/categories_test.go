@@ -0,0 +1,74 @@
+package postgresql_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/repository/postgresql"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+func TestCategoryRepo_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewCategoryRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery("SELECT id, name, slug, created_at FROM categories WHERE id = \\$1").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "slug", "created_at"}).
+			AddRow(1, "Politics", "politics", time.Now()))
+
+	category, err := repo.Get(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "politics", category.Slug)
+
+	mock.ExpectQuery("SELECT id, name, slug, created_at FROM categories WHERE id = \\$1").
+		WithArgs(2).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = repo.Get(context.Background(), 2)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCategoryRepo_Create_Duplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewCategoryRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	input := &domain.CategoryInput{Name: "Politics", Slug: "politics"}
+
+	mock.ExpectQuery("INSERT INTO categories").
+		WithArgs(input.Name, input.Slug).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	_, err = repo.Create(context.Background(), input)
+
+	assert.ErrorIs(t, err, errors.ErrAlreadyExists)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
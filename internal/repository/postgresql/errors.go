@@ -0,0 +1,17 @@
+package postgresql
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+func isPgError(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
@@ -0,0 +1,163 @@
+package postgresql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kokhno-nikolay/news/domain"
+)
+
+// postEventsChannel is the channel the posts trigger notifies on every
+// recorded event.
+const postEventsChannel = "post_events"
+
+// ListEvents returns the events recorded after afterID, oldest first.
+func (r *PostRepo) ListEvents(ctx context.Context, afterID int64, limit int) ([]*domain.PostEvent, error) {
+	query := `
+		SELECT id, type, post_id, COALESCE(author_id, 0), published, created_at
+		FROM post_events
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, afterID, getQueryLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.PostEvent
+
+	for rows.Next() {
+		var event domain.PostEvent
+		err := rows.Scan(&event.ID, &event.Type, &event.PostID, &event.AuthorID, &event.Published, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+// EventBounds returns the ids of the oldest and the latest recorded events,
+// both are zero while there are none.
+func (r *PostRepo) EventBounds(ctx context.Context) (first, last int64, err error) {
+	query := `SELECT COALESCE(MIN(id), 0), COALESCE(MAX(id), 0) FROM post_events`
+
+	err = r.db.QueryRowContext(ctx, query).Scan(&first, &last)
+
+	return first, last, err
+}
+
+// EventSnapshot returns the oldest transaction still running and the next
+// transaction to start. Event ids are taken before commit, an id missing
+// from ListEvents may still show up until every transaction that was
+// running when the gap was seen is over, that is once xmin reaches the
+// xmax seen back then.
+func (r *PostRepo) EventSnapshot(ctx context.Context) (xmin, xmax int64, err error) {
+	query := `
+		SELECT pg_snapshot_xmin(s)::text::bigint, pg_snapshot_xmax(s)::text::bigint
+		FROM pg_current_snapshot() s
+	`
+
+	err = r.db.QueryRowContext(ctx, query).Scan(&xmin, &xmax)
+
+	return xmin, xmax, err
+}
+
+// PurgeEvents removes up to limit events recorded before the given time and
+// returns how many were removed.
+func (r *PostRepo) PurgeEvents(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `
+		DELETE FROM post_events
+		WHERE id IN (
+			SELECT id
+			FROM post_events
+			WHERE created_at < $1
+			ORDER BY id
+			LIMIT $2
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, before, getQueryLimit(limit))
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
+
+// PostListener listens for the notifications of the posts trigger and wakes
+// up everyone waiting on Changed.
+type PostListener struct {
+	dns string
+
+	mu      sync.Mutex
+	changed chan struct{}
+}
+
+func NewPostListener(dns string) *PostListener {
+	return &PostListener{
+		dns:     dns,
+		changed: make(chan struct{}),
+	}
+}
+
+// Changed returns a channel that is closed on the next notification.
+func (l *PostListener) Changed() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.changed
+}
+
+// Run listens until ctx is done. Waiters are also woken up after a lost
+// connection is re-established, so they catch up on the events recorded
+// meanwhile.
+func (l *PostListener) Run(ctx context.Context) {
+	listener := pq.NewListener(l.dns, time.Second, time.Minute, func(_ pq.ListenerEventType, err error) {
+		if err != nil {
+			logrus.WithError(err).Warn("post listener: connection lost")
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(postEventsChannel); err != nil {
+		logrus.WithError(err).WithField("channel", postEventsChannel).Error("post listener: listening")
+		return
+	}
+
+	// a connection that went away silently is only noticed when used
+	ping := time.NewTicker(time.Minute)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-listener.Notify:
+			l.broadcast()
+		case <-ping.C:
+			go listener.Ping()
+		}
+	}
+}
+
+func (l *PostListener) broadcast() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
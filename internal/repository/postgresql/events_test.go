@@ -0,0 +1,117 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/repository/postgresql"
+)
+
+func TestPostRepo_ListEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT id, type, post_id, COALESCE\\(author_id, 0\\), published, created_at FROM post_events WHERE id > \\$1 ORDER BY id LIMIT \\$2").
+		WithArgs(int64(41), 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "post_id", "author_id", "published", "created_at"}).
+			AddRow(42, "created", 7, 3, false, now).
+			AddRow(43, "deleted", 5, 0, true, now))
+
+	events, err := repo.ListEvents(context.Background(), 41, 100)
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, domain.PostCreated, events[0].Type)
+	assert.Equal(t, 3, events[0].AuthorID)
+	assert.Equal(t, int64(43), events[1].ID)
+	assert.True(t, events[1].Published)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_EventBounds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery("SELECT COALESCE\\(MIN\\(id\\), 0\\), COALESCE\\(MAX\\(id\\), 0\\) FROM post_events").
+		WillReturnRows(sqlmock.NewRows([]string{"min", "max"}).AddRow(10, 42))
+
+	first, last, err := repo.EventBounds(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), first)
+	assert.Equal(t, int64(42), last)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_EventSnapshot(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery("SELECT pg_snapshot_xmin\\(s\\)::text::bigint, pg_snapshot_xmax\\(s\\)::text::bigint FROM pg_current_snapshot\\(\\) s").
+		WillReturnRows(sqlmock.NewRows([]string{"xmin", "xmax"}).AddRow(100, 105))
+
+	xmin, xmax, err := repo.EventSnapshot(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), xmin)
+	assert.Equal(t, int64(105), xmax)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_PurgeEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	before := time.Now().Add(-time.Hour)
+
+	mock.ExpectExec("DELETE FROM post_events WHERE id IN \\( SELECT id FROM post_events WHERE created_at < \\$1 ORDER BY id LIMIT \\$2 \\)").
+		WithArgs(before, 200).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	// a batch is capped like any other query
+	purged, err := repo.PurgeEvents(context.Background(), before, 1000)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, purged)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
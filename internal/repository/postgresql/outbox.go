@@ -0,0 +1,109 @@
+package postgresql
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kokhno-nikolay/news/domain"
+)
+
+// OutboxRepo hands post events over to the outbox relay.
+type OutboxRepo struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepo(db *sqlx.DB) *OutboxRepo {
+	return &OutboxRepo{
+		db: db,
+	}
+}
+
+// Claim takes up to limit events due for delivery, oldest first, and hides
+// them from other relays for the lease. An event that is neither delivered
+// nor failed by then is claimed again.
+func (r *OutboxRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.PostEvent, error) {
+	query := `
+		UPDATE post_events
+		SET attempts = attempts + 1,
+			next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id
+			FROM post_events
+			WHERE delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= NOW()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, type, post_id, COALESCE(author_id, 0), published, created_at, COALESCE(payload, '{}'),
+			COALESCE(payload->>'status', ''), COALESCE(previous_status, ''), attempts
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, getQueryLimit(limit), lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.PostEvent
+
+	for rows.Next() {
+		var event domain.PostEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.Type,
+			&event.PostID,
+			&event.AuthorID,
+			&event.Published,
+			&event.CreatedAt,
+			&event.Payload,
+			&event.Status,
+			&event.PreviousStatus,
+			&event.Attempts,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING follows no particular order
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+
+	return events, nil
+}
+
+// MarkDelivered records the successful delivery of an event.
+func (r *OutboxRepo) MarkDelivered(ctx context.Context, id int64) error {
+	query := `
+		UPDATE post_events
+		SET delivered_at = NOW(), last_error = NULL
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+
+	return err
+}
+
+// MarkFailed records a failed delivery. The event is retried at retryAt, a
+// nil retryAt gives the event up.
+func (r *OutboxRepo) MarkFailed(ctx context.Context, id int64, reason string, retryAt *time.Time) error {
+	query := `
+		UPDATE post_events
+		SET last_error = $2,
+			next_attempt_at = COALESCE($3, next_attempt_at),
+			failed_at = CASE WHEN $3::timestamptz IS NULL THEN NOW() END
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, reason, retryAt)
+
+	return err
+}
@@ -0,0 +1,93 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/repository/postgresql"
+)
+
+func TestOutboxRepo_Claim(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewOutboxRepo(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE post_events SET attempts = attempts \\+ 1, next_attempt_at = NOW\\(\\) \\+ make_interval\\(secs => \\$2\\) WHERE id IN \\( SELECT id FROM post_events WHERE delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= NOW\\(\\) ORDER BY id LIMIT \\$1 FOR UPDATE SKIP LOCKED \\)").
+		WithArgs(100, float64(60)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "post_id", "author_id", "published", "created_at", "payload", "status", "previous_status", "attempts"}).
+			AddRow(8, "updated", 1, 0, true, now, []byte(`{"id": 1}`), "published", "draft", 2).
+			AddRow(7, "created", 1, 0, false, now, []byte(`{"id": 1}`), "draft", "", 1))
+
+	events, err := repo.Claim(context.Background(), 100, time.Minute)
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, int64(7), events[0].ID)
+	assert.Equal(t, 2, events[1].Attempts)
+	assert.Equal(t, domain.PostStatusDraft, events[1].PreviousStatus)
+	assert.JSONEq(t, `{"id": 1}`, string(events[1].Payload))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOutboxRepo_MarkDelivered(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewOutboxRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec("UPDATE post_events SET delivered_at = NOW\\(\\), last_error = NULL WHERE id = \\$1").
+		WithArgs(int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MarkDelivered(context.Background(), 7))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOutboxRepo_MarkFailed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewOutboxRepo(sqlx.NewDb(db, "sqlmock"))
+
+	retryAt := time.Now().Add(time.Minute)
+
+	mock.ExpectExec("UPDATE post_events SET last_error = \\$2, next_attempt_at = COALESCE\\(\\$3, next_attempt_at\\)").
+		WithArgs(int64(7), "connection refused", retryAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// without a retry the event is given up
+	mock.ExpectExec("UPDATE post_events SET last_error = \\$2").
+		WithArgs(int64(7), "connection refused", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MarkFailed(context.Background(), 7, "connection refused", &retryAt))
+	assert.NoError(t, repo.MarkFailed(context.Background(), 7, "connection refused", nil))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
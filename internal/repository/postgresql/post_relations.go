@@ -0,0 +1,165 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+	"github.com/kokhno-nikolay/news/pkg/slug"
+)
+
+// setPostTaxonomy replaces the categories and tags of a post. Nil slices in
+// the input keep the corresponding links as they are.
+func setPostTaxonomy(ctx context.Context, tx *sqlx.Tx, postID int, input *domain.PostInput) error {
+	if input.CategoryIDs != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_categories WHERE post_id = $1`, postID); err != nil {
+			return err
+		}
+
+		if len(input.CategoryIDs) > 0 {
+			query := `
+				INSERT INTO post_categories (post_id, category_id)
+				SELECT $1, unnest($2::int[])
+			`
+
+			_, err := tx.ExecContext(ctx, query, postID, pq.Array(input.CategoryIDs))
+			if err != nil {
+				if isPgError(err, foreignKeyViolation) {
+					return fmt.Errorf("category: %w", errors.ErrNotFound)
+				}
+
+				return err
+			}
+		}
+	}
+
+	if input.Tags != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_tags WHERE post_id = $1`, postID); err != nil {
+			return err
+		}
+
+		if len(input.Tags) > 0 {
+			slugs := make([]string, len(input.Tags))
+			for i, name := range input.Tags {
+				slugs[i] = slug.Make(name)
+			}
+
+			// missing tags are created, existing ones are matched by slug
+			query := `
+				WITH upserted AS (
+					INSERT INTO tags (name, slug)
+					SELECT * FROM unnest($2::text[], $3::text[])
+					ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+					RETURNING id
+				)
+				INSERT INTO post_tags (post_id, tag_id)
+				SELECT $1, id FROM upserted
+			`
+
+			_, err := tx.ExecContext(ctx, query, postID, pq.Array(input.Tags), pq.Array(slugs))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadPostRelations fills authors, categories and tags of the given posts.
+func loadPostRelations(ctx context.Context, q sqlx.QueryerContext, posts []*domain.Post) error {
+	if err := loadPostAuthors(ctx, q, posts); err != nil {
+		return err
+	}
+
+	return loadPostTaxonomy(ctx, q, posts)
+}
+
+// loadPostTaxonomy fills categories and tags of the given posts with two
+// queries, whatever the number of posts is.
+func loadPostTaxonomy(ctx context.Context, q sqlx.QueryerContext, posts []*domain.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(posts))
+	byID := make(map[int]*domain.Post, len(posts))
+	for i, post := range posts {
+		ids[i] = int64(post.ID)
+		byID[post.ID] = post
+	}
+
+	if err := loadPostCategories(ctx, q, ids, byID); err != nil {
+		return err
+	}
+
+	return loadPostTags(ctx, q, ids, byID)
+}
+
+func loadPostCategories(ctx context.Context, q sqlx.QueryerContext, ids []int64, byID map[int]*domain.Post) error {
+	query := `
+		SELECT pc.post_id, c.id, c.name, c.slug, c.created_at
+		FROM post_categories pc
+		JOIN categories c ON c.id = pc.category_id
+		WHERE pc.post_id = ANY($1)
+		ORDER BY c.name
+	`
+
+	rows, err := q.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			postID   int
+			category domain.Category
+		)
+		if err := rows.Scan(&postID, &category.ID, &category.Name, &category.Slug, &category.CreatedAt); err != nil {
+			return err
+		}
+
+		if post, ok := byID[postID]; ok {
+			post.Categories = append(post.Categories, &category)
+		}
+	}
+
+	return rows.Err()
+}
+
+func loadPostTags(ctx context.Context, q sqlx.QueryerContext, ids []int64, byID map[int]*domain.Post) error {
+	query := `
+		SELECT pt.post_id, t.id, t.name, t.slug, t.created_at
+		FROM post_tags pt
+		JOIN tags t ON t.id = pt.tag_id
+		WHERE pt.post_id = ANY($1)
+		ORDER BY t.name
+	`
+
+	rows, err := q.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			postID int
+			tag    domain.Tag
+		)
+		if err := rows.Scan(&postID, &tag.ID, &tag.Name, &tag.Slug, &tag.CreatedAt); err != nil {
+			return err
+		}
+
+		if post, ok := byID[postID]; ok {
+			post.Tags = append(post.Tags, &tag)
+		}
+	}
+
+	return rows.Err()
+}
@@ -3,15 +3,68 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
 
 	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/logging"
 	"github.com/kokhno-nikolay/news/pkg/errors"
 )
 
 const defaultLimit = 200
 
+// MaxLimit is the most rows a single query returns, larger limits are
+// lowered to it.
+const MaxLimit = defaultLimit
+
+// sortColumns whitelists the columns a post list can be ordered by, sort
+// fields are never interpolated into queries directly.
+var sortColumns = map[domain.SortField]string{
+	domain.SortByCreatedAt: "created_at",
+	domain.SortByUpdatedAt: "updated_at",
+	domain.SortByTitle:     "title",
+}
+
+const (
+	titleHeadlineOptions   = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+	snippetHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// postColumns is selected by every query returning posts, scanPost reads the
+// columns in the same order.
+const postColumns = `id, title, content, COALESCE(author_id, 0), status, published_at, publish_at, created_at, updated_at, deleted_at, version`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPost reads postColumns into post, extra destinations are scanned from
+// the columns that follow.
+func scanPost(row rowScanner, post *domain.Post, extra ...interface{}) error {
+	dest := []interface{}{
+		&post.ID,
+		&post.Title,
+		&post.Content,
+		&post.AuthorID,
+		&post.Status,
+		&post.PublishedAt,
+		&post.PublishAt,
+		&post.CreatedAt,
+		&post.UpdatedAt,
+		&post.DeletedAt,
+		&post.Version,
+	}
+
+	return row.Scan(append(dest, extra...)...)
+}
+
 type PostRepo struct {
 	db *sqlx.DB
 }
@@ -22,23 +75,25 @@ func NewPostRepo(db *sqlx.DB) *PostRepo {
 	}
 }
 
+// Get returns a post that is not in the trash.
 func (r *PostRepo) Get(ctx context.Context, id int) (*domain.Post, error) {
+	return r.get(ctx, id, false)
+}
+
+func (r *PostRepo) get(ctx context.Context, id int, trashed bool) (*domain.Post, error) {
 	query := `
-		SELECT id, title, content, created_at, updated_at 
+		SELECT ` + postColumns + `
 		FROM posts
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
+	if trashed {
+		query = strings.Replace(query, "deleted_at IS NULL", "deleted_at IS NOT NULL", 1)
+	}
 
-	row := r.db.QueryRow(query, &id)
+	row := r.db.QueryRowContext(ctx, query, id)
 
 	var post domain.Post
-	err := row.Scan(
-		&post.ID,
-		&post.Title,
-		&post.Content,
-		&post.CreatedAt,
-		&post.UpdatedAt,
-	)
+	err := scanPost(row, &post)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.ErrNotFound
@@ -47,19 +102,57 @@ func (r *PostRepo) Get(ctx context.Context, id int) (*domain.Post, error) {
 		return nil, err
 	}
 
+	if err := loadPostRelations(ctx, r.db, []*domain.Post{&post}); err != nil {
+		return nil, err
+	}
+
 	return &post, nil
 }
 
-func (r *PostRepo) List(ctx context.Context, limit *int) ([]*domain.Post, error) {
-	queryLimit := getQueryLimit(limit)
+// List returns posts in the requested sort order. When a cursor is given,
+// only posts strictly after it (or before it, for backward cursors) are
+// returned, but the result always keeps the requested order.
+func (r *PostRepo) List(ctx context.Context, params *domain.PostListParams) ([]*domain.Post, error) {
+	column, ok := sortColumns[params.Sort.Field]
+	if !ok {
+		return nil, errors.ErrInvalidSortField
+	}
+
+	where, args := buildPostFilter(&params.Filter)
+
+	// scanning backward flips both the comparison and the order, the rows
+	// are reversed after reading
+	desc := params.Sort.Desc
+	if params.Cursor != nil && params.Cursor.Backward {
+		desc = !desc
+	}
+
+	op, order := ">", "ASC"
+	if desc {
+		op, order = "<", "DESC"
+	}
+
+	if params.Cursor != nil {
+		args = append(args, params.Cursor.Value(), params.Cursor.ID)
+		where = append(where, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, op, len(args)-1, len(args)))
+	}
 
 	query := `
-		SELECT id, title, content, created_at, updated_at
+		SELECT ` + postColumns + `
 		FROM posts
-		LIMIT $1
-	`
+		WHERE ` + strings.Join(where, " AND ")
+
+	// the service asks for one row past the page to tell whether there is
+	// another one, that row is not counted against the cap
+	args = append(args, getQueryLimit(params.Limit-1)+1)
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT $%d", column, order, order, len(args))
+
+	if params.Offset > 0 {
+		args = append(args, params.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, queryLimit)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -69,14 +162,7 @@ func (r *PostRepo) List(ctx context.Context, limit *int) ([]*domain.Post, error)
 
 	for rows.Next() {
 		var post domain.Post
-		err := rows.Scan(
-			&post.ID,
-			&post.Title,
-			&post.Content,
-			&post.CreatedAt,
-			&post.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanPost(rows, &post); err != nil {
 			return nil, err
 		}
 		postList = append(postList, &post)
@@ -86,48 +172,325 @@ func (r *PostRepo) List(ctx context.Context, limit *int) ([]*domain.Post, error)
 		return nil, err
 	}
 
+	if params.Cursor != nil && params.Cursor.Backward {
+		for i, j := 0, len(postList)-1; i < j; i, j = i+1, j-1 {
+			postList[i], postList[j] = postList[j], postList[i]
+		}
+	}
+
+	if err := loadPostRelations(ctx, r.db, postList); err != nil {
+		return nil, err
+	}
+
 	return postList, nil
 }
 
+func (r *PostRepo) Count(ctx context.Context, filter *domain.PostFilter) (int, error) {
+	where, args := buildPostFilter(filter)
+
+	query := `
+		SELECT COUNT(*)
+		FROM posts
+		WHERE ` + strings.Join(where, " AND ")
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Search ranks published posts matching a web-search style query (quoted
+// phrases, OR and -exclusions are supported) by relevance, titles weighing
+// more than content.
+func (r *PostRepo) Search(ctx context.Context, params *domain.SearchParams) ([]*domain.SearchResult, error) {
+	query := `
+		SELECT ` + postColumns + `,
+			ts_rank_cd(search_vector, q) AS rank,
+			ts_headline($1::regconfig, title, q, $5) AS title_highlight,
+			ts_headline($1::regconfig, content, q, $6) AS snippet
+		FROM posts, websearch_to_tsquery($1::regconfig, $2) AS q
+		WHERE search_vector @@ q AND status = 'published' AND deleted_at IS NULL
+		ORDER BY rank DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		params.Language,
+		params.Query,
+		getQueryLimit(params.Limit),
+		params.Offset,
+		titleHeadlineOptions,
+		snippetHeadlineOptions,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*domain.SearchResult
+
+	for rows.Next() {
+		var (
+			post   domain.Post
+			result = domain.SearchResult{Post: &post}
+		)
+		err := scanPost(rows, &post, &result.Rank, &result.TitleHighlight, &result.Snippet)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	posts := make([]*domain.Post, len(results))
+	for i, result := range results {
+		posts[i] = result.Post
+	}
+
+	if err := loadPostRelations(ctx, r.db, posts); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
 func (r *PostRepo) Create(ctx context.Context, input *domain.PostInput) (*domain.Post, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	query := `
-        INSERT INTO posts (title, content) 
-        VALUES ($1, $2) 
-		RETURNING id, title, content, created_at
+        INSERT INTO posts (title, content, author_id, search_language) 
+        VALUES ($1, $2, NULLIF($3, 0), $4) 
+		RETURNING ` + postColumns + `
     `
 
 	var post domain.Post
-	err := r.db.QueryRowContext(ctx, query, input.Title, input.Content).
-		Scan(&post.ID, &post.Title, &post.Content, &post.CreatedAt)
+	err = scanPost(tx.QueryRowContext(ctx, query, input.Title, input.Content, input.AuthorID, input.SearchLanguage), &post)
 	if err != nil {
+		if isPgError(err, foreignKeyViolation) {
+			return nil, fmt.Errorf("author: %w", errors.ErrNotFound)
+		}
+
+		return nil, err
+	}
+
+	if err := insertPostRevision(ctx, tx, &post, input.EditedBy); err != nil {
+		return nil, err
+	}
+
+	if err := loadPostAuthors(ctx, tx, []*domain.Post{&post}); err != nil {
+		return nil, err
+	}
+
+	// a fresh post has no links unless the input brings them
+	if input.CategoryIDs != nil || input.Tags != nil {
+		if err := setPostTaxonomy(ctx, tx, post.ID, input); err != nil {
+			return nil, err
+		}
+
+		if err := loadPostTaxonomy(ctx, tx, []*domain.Post{&post}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
 	return &post, nil
 }
 
+// Update changes the post only when it is still at input.Version, otherwise
+// it fails with ErrVersionConflict.
 func (r *PostRepo) Update(ctx context.Context, id int, input *domain.PostInput) (*domain.Post, error) {
-	query := `
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	updatedPost, err := updatePostTx(ctx, tx, id, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadPostRelations(ctx, tx, []*domain.Post{updatedPost}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return updatedPost, nil
+}
+
+// updatePostTx updates a post along with its revision and links, leaving
+// the relations of the returned post to the caller.
+func updatePostTx(ctx context.Context, tx *sqlx.Tx, id int, input *domain.PostInput) (*domain.Post, error) {
+	// an update limited to the links still bumps the version of the post
+	set := []string{"updated_at = NOW()"}
+	args := []interface{}{}
+
+	if input.Changes(domain.PostFieldTitle) {
+		args = append(args, input.Title)
+		set = append(set, fmt.Sprintf("title = $%d", len(args)))
+	}
+
+	if input.Changes(domain.PostFieldContent) {
+		args = append(args, input.Content)
+		set = append(set, fmt.Sprintf("content = $%d", len(args)))
+	}
+
+	if len(set) > 1 {
+		set = set[1:]
+	}
+
+	args = append(args, id, input.Version)
+	query := fmt.Sprintf(`
 		UPDATE posts
-		SET title = $1, content = $2
-		WHERE id = $3
-		RETURNING id, title, content, created_at, updated_at
-	`
+		SET %s
+		WHERE id = $%d AND deleted_at IS NULL AND version = $%d
+		RETURNING `+postColumns+`
+	`, strings.Join(set, ", "), len(args)-1, len(args))
 
 	var updatedPost domain.Post
-	err := r.db.QueryRowContext(ctx, query, input.Title, input.Content, id).
-		Scan(&updatedPost.ID, &updatedPost.Title, &updatedPost.Content, &updatedPost.CreatedAt, &updatedPost.UpdatedAt)
+	err := scanPost(tx.QueryRowContext(ctx, query, args...), &updatedPost)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			err = postVersionError(ctx, tx, id)
+			if err == errors.ErrVersionConflict {
+				logging.FromContext(ctx).WithFields(logrus.Fields{
+					"post_id": id,
+					"version": input.Version,
+				}).Debug("post update lost to a concurrent edit")
+			}
+
+			return nil, err
+		}
+
+		return nil, err
+	}
+
+	// links are not part of a revision
+	if input.Changes(domain.PostFieldTitle) || input.Changes(domain.PostFieldContent) {
+		if err := insertPostRevision(ctx, tx, &updatedPost, input.EditedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := setPostTaxonomy(ctx, tx, id, input); err != nil {
 		return nil, err
 	}
 
 	return &updatedPost, nil
 }
 
+// UpdateStatus moves a post from one status to another. The current status
+// is part of the condition, so a post changed concurrently is reported as
+// not found instead of being overwritten. published_at keeps the date of the
+// first publication, publishing or archiving drops a pending schedule.
+func (r *PostRepo) UpdateStatus(ctx context.Context, id int, from, to domain.PostStatus) (*domain.Post, error) {
+	query := `
+		UPDATE posts
+		SET status = $3,
+			published_at = CASE WHEN $3 = 'published' THEN COALESCE(published_at, NOW()) ELSE published_at END,
+			publish_at = CASE WHEN $3 IN ('published', 'archived') THEN NULL ELSE publish_at END
+		WHERE id = $1 AND status = $2 AND deleted_at IS NULL
+		RETURNING ` + postColumns + `
+	`
+
+	return r.updatePost(ctx, query, id, from, to)
+}
+
+// SetPublishAt schedules a post in the given status, a nil publishAt cancels
+// the schedule. Like UpdateStatus it reports a post whose status has changed
+// as not found.
+func (r *PostRepo) SetPublishAt(ctx context.Context, id int, status domain.PostStatus, publishAt *time.Time) (*domain.Post, error) {
+	query := `
+		UPDATE posts
+		SET publish_at = $3
+		WHERE id = $1 AND status = $2 AND deleted_at IS NULL
+		RETURNING ` + postColumns + `
+	`
+
+	return r.updatePost(ctx, query, id, status, publishAt)
+}
+
+// PublishDue publishes up to limit posts whose publish_at has come and returns
+// their ids. Rows locked by another replica are skipped, so concurrent
+// schedulers never publish the same post twice.
+func (r *PostRepo) PublishDue(ctx context.Context, now time.Time, limit int) ([]int, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM posts
+			WHERE publish_at <= $1 AND status IN ('draft', 'in_review') AND deleted_at IS NULL
+			ORDER BY publish_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE posts
+		SET status = 'published',
+			published_at = COALESCE(posts.published_at, posts.publish_at),
+			publish_at = NULL
+		FROM due
+		WHERE posts.id = due.id
+		RETURNING posts.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now, getQueryLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// updatePost runs an UPDATE ... RETURNING postColumns for a single post.
+func (r *PostRepo) updatePost(ctx context.Context, query string, args ...interface{}) (*domain.Post, error) {
+	var post domain.Post
+	err := scanPost(r.db.QueryRowContext(ctx, query, args...), &post)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	if err := loadPostRelations(ctx, r.db, []*domain.Post{&post}); err != nil {
+		return nil, err
+	}
+
+	return &post, nil
+}
+
+// Delete moves a post to the trash, see Restore and Purge.
 func (r *PostRepo) Delete(ctx context.Context, id int) (bool, error) {
 	query := `
-		DELETE FROM posts 
-		WHERE id = $1
+		UPDATE posts
+		SET deleted_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	result, err := r.db.ExecContext(ctx, query, id)
@@ -143,10 +506,88 @@ func (r *PostRepo) Delete(ctx context.Context, id int) (bool, error) {
 	return rowsAffected > 0, nil
 }
 
-func getQueryLimit(limit *int) int {
-	if limit != nil && *limit <= defaultLimit {
-		return *limit
+// postVersionError tells a post that does not exist from one that has moved
+// past the expected version.
+func postVersionError(ctx context.Context, tx *sqlx.Tx, id int) error {
+	var exists bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM posts WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return errors.ErrNotFound
+	}
+
+	return errors.ErrVersionConflict
+}
+
+func getQueryLimit(limit int) int {
+	if limit > 0 && limit <= defaultLimit {
+		return limit
 	}
 
 	return defaultLimit
 }
+
+// buildPostFilter returns WHERE conditions for the filter together with
+// their positional arguments, numbered from $1. Trashed posts never match.
+func buildPostFilter(filter *domain.PostFilter) ([]string, []interface{}) {
+	var (
+		where = []string{"deleted_at IS NULL"}
+		args  []interface{}
+	)
+
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.CreatedFrom != nil {
+		add("created_at >= $%d", *filter.CreatedFrom)
+	}
+
+	if filter.CreatedTo != nil {
+		add("created_at < $%d", *filter.CreatedTo)
+	}
+
+	if filter.UpdatedFrom != nil {
+		add("updated_at >= $%d", *filter.UpdatedFrom)
+	}
+
+	if filter.UpdatedTo != nil {
+		add("updated_at < $%d", *filter.UpdatedTo)
+	}
+
+	if filter.TitleContains != "" {
+		add(`title ILIKE $%d ESCAPE '\'`, "%"+likeEscaper.Replace(filter.TitleContains)+"%")
+	}
+
+	if filter.Tag != "" {
+		add(`EXISTS (
+			SELECT 1 FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+			WHERE pt.post_id = posts.id AND t.slug = $%d
+		)`, filter.Tag)
+	}
+
+	if filter.CategoryID != 0 {
+		add(`EXISTS (
+			SELECT 1 FROM post_categories pc
+			WHERE pc.post_id = posts.id AND pc.category_id = $%d
+		)`, filter.CategoryID)
+	}
+
+	if filter.AuthorID != 0 {
+		add("author_id = $%d", filter.AuthorID)
+	}
+
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		add("status = ANY($%d)", pq.Array(statuses))
+	}
+
+	return where, args
+}
@@ -11,8 +11,35 @@ import (
 
 	"github.com/kokhno-nikolay/news/domain"
 	"github.com/kokhno-nikolay/news/internal/repository/postgresql"
+	"github.com/kokhno-nikolay/news/pkg/errors"
 )
 
+// postColumns matches the column list of every query returning posts.
+const postColumns = "id, title, content, COALESCE\\(author_id, 0\\), status, published_at, publish_at, created_at, updated_at, deleted_at, version"
+
+// newPostRows returns rows shaped like postColumns followed by extra columns.
+func newPostRows(extra ...string) *sqlmock.Rows {
+	columns := []string{"id", "title", "content", "author_id", "status", "published_at", "publish_at", "created_at", "updated_at", "deleted_at", "version"}
+	return sqlmock.NewRows(append(columns, extra...))
+}
+
+// expectPostRevision expects the revision recorded by every write of a
+// post's text.
+func expectPostRevision(mock sqlmock.Sqlmock, postID int) {
+	mock.ExpectExec("INSERT INTO post_revisions \\(post_id, revision, title, content, edited_by\\)").
+		WithArgs(postID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+// expectPostTaxonomy expects the category and tag lookups that follow every
+// query returning posts.
+func expectPostTaxonomy(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT pc.post_id, c.id, c.name, c.slug, c.created_at FROM post_categories pc").
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "id", "name", "slug", "created_at"}))
+	mock.ExpectQuery("SELECT pt.post_id, t.id, t.name, t.slug, t.created_at FROM post_tags pt").
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "id", "name", "slug", "created_at"}))
+}
+
 func TestPostsRepo_Get(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -30,22 +57,31 @@ func TestPostsRepo_Get(t *testing.T) {
 		ID:        id,
 		Title:     "Test Title",
 		Content:   "Test Content",
+		Status:    domain.PostStatusPublished,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	mock.ExpectQuery("SELECT id, title, content, created_at, updated_at FROM posts WHERE id = ?").
+	mock.ExpectQuery("SELECT " + postColumns + " FROM posts WHERE id = \\$1 AND deleted_at IS NULL").
 		WithArgs(id).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "created_at", "updated_at"}).
+		WillReturnRows(newPostRows().
 			AddRow(
 				expectedPost.ID,
 				expectedPost.Title,
 				expectedPost.Content,
+				expectedPost.AuthorID,
+				expectedPost.Status,
+				expectedPost.PublishedAt,
+				expectedPost.PublishAt,
 				expectedPost.CreatedAt,
 				expectedPost.UpdatedAt,
+				expectedPost.DeletedAt,
+				expectedPost.Version,
 			),
 		)
 
+	expectPostTaxonomy(mock)
+
 	retrievedPost, err := repo.Get(context.Background(), id)
 
 	assert.NoError(t, err)
@@ -53,6 +89,7 @@ func TestPostsRepo_Get(t *testing.T) {
 	assert.Equal(t, expectedPost.ID, retrievedPost.ID)
 	assert.Equal(t, expectedPost.Title, retrievedPost.Title)
 	assert.Equal(t, expectedPost.Content, retrievedPost.Content)
+	assert.Equal(t, expectedPost.Status, retrievedPost.Status)
 	assert.WithinDuration(t, expectedPost.CreatedAt, retrievedPost.CreatedAt, time.Second)
 	assert.WithinDuration(t, expectedPost.UpdatedAt, retrievedPost.UpdatedAt, time.Second)
 
@@ -61,6 +98,30 @@ func TestPostsRepo_Get(t *testing.T) {
 	}
 }
 
+func TestPostsRepo_Get_Canceled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery("SELECT " + postColumns + " FROM posts WHERE id = \\$1 AND deleted_at IS NULL").
+		WithArgs(1).
+		WillDelayFor(time.Second).
+		WillReturnRows(newPostRows())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// the query is canceled along with the request instead of running on
+	post, err := repo.Get(ctx, 1)
+
+	assert.Error(t, err)
+	assert.Nil(t, post)
+}
+
 func TestPostRepo_List(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -98,17 +159,22 @@ func TestPostRepo_List(t *testing.T) {
 		},
 	}
 
-	mock.ExpectQuery("SELECT id, title, content, created_at, updated_at FROM posts LIMIT \\$1").
+	mock.ExpectQuery("SELECT " + postColumns + " FROM posts WHERE deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT \\$1").
 		WithArgs(limit).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "created_at", "updated_at"}).
-			AddRow(expectedPost[0].ID, expectedPost[0].Title, expectedPost[0].Content,
-				expectedPost[0].CreatedAt, expectedPost[0].UpdatedAt).
-			AddRow(expectedPost[1].ID, expectedPost[1].Title, expectedPost[1].Content,
-				expectedPost[1].CreatedAt, expectedPost[1].UpdatedAt).
-			AddRow(expectedPost[2].ID, expectedPost[2].Title, expectedPost[2].Content,
-				expectedPost[2].CreatedAt, expectedPost[2].UpdatedAt))
+		WillReturnRows(newPostRows().
+			AddRow(expectedPost[0].ID, expectedPost[0].Title, expectedPost[0].Content, 0, "published", nil, nil,
+				expectedPost[0].CreatedAt, expectedPost[0].UpdatedAt, nil, 1).
+			AddRow(expectedPost[1].ID, expectedPost[1].Title, expectedPost[1].Content, 0, "published", nil, nil,
+				expectedPost[1].CreatedAt, expectedPost[1].UpdatedAt, nil, 1).
+			AddRow(expectedPost[2].ID, expectedPost[2].Title, expectedPost[2].Content, 0, "published", nil, nil,
+				expectedPost[2].CreatedAt, expectedPost[2].UpdatedAt, nil, 1))
+
+	expectPostTaxonomy(mock)
 
-	postList, err := repo.List(context.Background(), &limit)
+	postList, err := repo.List(context.Background(), &domain.PostListParams{
+		Sort:  domain.PostSort{Field: domain.SortByCreatedAt, Desc: true},
+		Limit: limit,
+	})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, postList)
@@ -127,6 +193,186 @@ func TestPostRepo_List(t *testing.T) {
 	}
 }
 
+func TestPostRepo_List_Cursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	now := time.Now()
+	sort := domain.PostSort{Field: domain.SortByCreatedAt, Desc: true}
+	cursor := &domain.Cursor{Sort: sort, CreatedAt: now, ID: 10, Backward: true}
+	limit := 2
+
+	// Rows come back oldest first for backward cursors
+	mock.ExpectQuery("SELECT "+postColumns+" FROM posts WHERE deleted_at IS NULL AND \\(created_at, id\\) > \\(\\$1, \\$2\\) ORDER BY created_at ASC, id ASC LIMIT \\$3").
+		WithArgs(cursor.CreatedAt, cursor.ID, limit).
+		WillReturnRows(newPostRows().
+			AddRow(11, "Test Title 11", "Test Content 11", 0, "published", nil, nil, now.Add(time.Minute), now.Add(time.Minute), nil, 1).
+			AddRow(12, "Test Title 12", "Test Content 12", 0, "published", nil, nil, now.Add(2*time.Minute), now.Add(2*time.Minute), nil, 1))
+
+	expectPostTaxonomy(mock)
+
+	postList, err := repo.List(context.Background(), &domain.PostListParams{Sort: sort, Limit: limit, Cursor: cursor})
+
+	assert.NoError(t, err)
+	assert.Len(t, postList, 2)
+	assert.Equal(t, 12, postList[0].ID)
+	assert.Equal(t, 11, postList[1].ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_List_LimitCap(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// A full page plus the row telling whether there is another one
+	mock.ExpectQuery("SELECT " + postColumns + " FROM posts WHERE deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT \\$1").
+		WithArgs(201).
+		WillReturnRows(newPostRows())
+
+	// Larger limits are capped
+	mock.ExpectQuery("SELECT " + postColumns + " FROM posts WHERE deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT \\$1").
+		WithArgs(201).
+		WillReturnRows(newPostRows())
+
+	sort := domain.PostSort{Field: domain.SortByCreatedAt, Desc: true}
+
+	_, err = repo.List(context.Background(), &domain.PostListParams{Sort: sort, Limit: 201})
+	assert.NoError(t, err)
+
+	_, err = repo.List(context.Background(), &domain.PostListParams{Sort: sort, Limit: 10000})
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_List_FilterAndSort(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	from := time.Now().Add(-time.Hour)
+	params := &domain.PostListParams{
+		Filter: domain.PostFilter{
+			UpdatedFrom:   &from,
+			TitleContains: "50%_off",
+		},
+		Sort:  domain.PostSort{Field: domain.SortByTitle},
+		Limit: 10,
+	}
+
+	mock.ExpectQuery("SELECT "+postColumns+" FROM posts WHERE deleted_at IS NULL AND updated_at >= \\$1 AND title ILIKE \\$2 ESCAPE '\\\\' ORDER BY title ASC, id ASC LIMIT \\$3").
+		WithArgs(from, `%50\%\_off%`, params.Limit).
+		WillReturnRows(newPostRows().
+			AddRow(1, "50%_off everything", "Test Content", 0, "published", from, nil, from, from, nil, 1))
+
+	expectPostTaxonomy(mock)
+
+	postList, err := repo.List(context.Background(), params)
+
+	assert.NoError(t, err)
+	assert.Len(t, postList, 1)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_List_UnknownSortField(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	_, err = repo.List(context.Background(), &domain.PostListParams{
+		Sort: domain.PostSort{Field: "content; DROP TABLE posts"},
+	})
+
+	assert.ErrorIs(t, err, errors.ErrInvalidSortField)
+}
+
+func TestPostRepo_Count(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM posts WHERE deleted_at IS NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	count, err := repo.Count(context.Background(), &domain.PostFilter{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, count)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_Search(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	params := &domain.SearchParams{
+		Query:    "election results",
+		Language: "english",
+		Limit:    10,
+	}
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM posts, websearch_to_tsquery\\(\\$1::regconfig, \\$2\\) AS q WHERE search_vector @@ q AND status = 'published' AND deleted_at IS NULL ORDER BY rank DESC, id DESC LIMIT \\$3 OFFSET \\$4").
+		WithArgs(params.Language, params.Query, params.Limit, 0, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(newPostRows("rank", "title_highlight", "snippet").
+			AddRow(1, "Election results", "Test Content", 0, "published", now, nil, now, now, nil, 1, 0.8, "<mark>Election</mark> <mark>results</mark>", "Test Content"))
+
+	expectPostTaxonomy(mock)
+
+	results, err := repo.Search(context.Background(), params)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, 1, results[0].Post.ID)
+	assert.Equal(t, 0.8, results[0].Rank)
+	assert.Equal(t, "<mark>Election</mark> <mark>results</mark>", results[0].TitleHighlight)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
 func TestPostRepo_Create(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -138,8 +384,9 @@ func TestPostRepo_Create(t *testing.T) {
 
 	// Input data for testing
 	input := &domain.PostInput{
-		Title:   "Test Title",
-		Content: "Test Content",
+		Title:          "Test Title",
+		Content:        "Test Content",
+		SearchLanguage: "english",
 	}
 
 	// Expected data after retrieval
@@ -147,20 +394,32 @@ func TestPostRepo_Create(t *testing.T) {
 		ID:        1,
 		Title:     input.Title,
 		Content:   input.Content,
+		Status:    domain.PostStatusDraft,
 		CreatedAt: time.Now(),
 	}
 
+	mock.ExpectBegin()
 	mock.ExpectQuery("INSERT INTO posts").
-		WithArgs(input.Title, input.Content).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "created_at"}).
+		WithArgs(input.Title, input.Content, input.AuthorID, input.SearchLanguage).
+		WillReturnRows(newPostRows().
 			AddRow(
 				expectedPost.ID,
 				expectedPost.Title,
 				expectedPost.Content,
+				expectedPost.AuthorID,
+				expectedPost.Status,
+				expectedPost.PublishedAt,
+				expectedPost.PublishAt,
+				expectedPost.CreatedAt,
 				expectedPost.CreatedAt,
+				nil,
+				1,
 			),
 		)
 
+	expectPostRevision(mock, expectedPost.ID)
+	mock.ExpectCommit()
+
 	createdPost, err := repo.Create(context.Background(), input)
 
 	assert.NoError(t, err)
@@ -168,6 +427,7 @@ func TestPostRepo_Create(t *testing.T) {
 	assert.Equal(t, expectedPost.ID, createdPost.ID)
 	assert.Equal(t, expectedPost.Title, createdPost.Title)
 	assert.Equal(t, expectedPost.Content, createdPost.Content)
+	assert.Equal(t, domain.PostStatusDraft, createdPost.Status)
 	assert.WithinDuration(t, expectedPost.CreatedAt, createdPost.CreatedAt, time.Second)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
@@ -175,6 +435,64 @@ func TestPostRepo_Create(t *testing.T) {
 	}
 }
 
+func TestPostRepo_Create_WithTaxonomy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	input := &domain.PostInput{
+		Title:       "Test Title",
+		Content:     "Test Content",
+		CategoryIDs: []int{3},
+		Tags:        []string{"Elections"},
+	}
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO posts").
+		WithArgs(input.Title, input.Content, input.AuthorID, input.SearchLanguage).
+		WillReturnRows(newPostRows().
+			AddRow(1, input.Title, input.Content, 0, "draft", nil, nil, now, now, nil, 1))
+	expectPostRevision(mock, 1)
+	mock.ExpectExec("DELETE FROM post_categories WHERE post_id = \\$1").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO post_categories").
+		WithArgs(1, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM post_tags WHERE post_id = \\$1").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO tags (.+) INSERT INTO post_tags").
+		WithArgs(1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT pc.post_id, c.id, c.name, c.slug, c.created_at FROM post_categories pc").
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "id", "name", "slug", "created_at"}).
+			AddRow(1, 3, "Politics", "politics", now))
+	mock.ExpectQuery("SELECT pt.post_id, t.id, t.name, t.slug, t.created_at FROM post_tags pt").
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "id", "name", "slug", "created_at"}).
+			AddRow(1, 7, "Elections", "elections", now))
+	mock.ExpectCommit()
+
+	createdPost, err := repo.Create(context.Background(), input)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, createdPost)
+	assert.Len(t, createdPost.Categories, 1)
+	assert.Equal(t, "politics", createdPost.Categories[0].Slug)
+	assert.Len(t, createdPost.Tags, 1)
+	assert.Equal(t, "elections", createdPost.Tags[0].Slug)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
 func TestPostRepo_Update(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -190,6 +508,7 @@ func TestPostRepo_Update(t *testing.T) {
 	updateInput := &domain.PostInput{
 		Title:   "Updated Title",
 		Content: "Updated Content",
+		Version: 3,
 	}
 
 	// Expected data after update
@@ -197,14 +516,21 @@ func TestPostRepo_Update(t *testing.T) {
 		ID:        id,
 		Title:     updateInput.Title,
 		Content:   updateInput.Content,
+		Status:    domain.PostStatusDraft,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+		Version:   4,
 	}
 
-	mock.ExpectQuery("UPDATE posts SET title = \\$1, content = \\$2 WHERE id = \\$3 RETURNING id, title, content, created_at, updated_at").
-		WithArgs(updateInput.Title, updateInput.Content, id).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "content", "created_at", "updated_at"}).
-			AddRow(expectedPost.ID, expectedPost.Title, expectedPost.Content, expectedPost.CreatedAt, expectedPost.UpdatedAt))
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE posts SET title = \\$1, content = \\$2 WHERE id = \\$3 AND deleted_at IS NULL AND version = \\$4 RETURNING "+postColumns).
+		WithArgs(updateInput.Title, updateInput.Content, id, updateInput.Version).
+		WillReturnRows(newPostRows().
+			AddRow(expectedPost.ID, expectedPost.Title, expectedPost.Content, expectedPost.AuthorID, expectedPost.Status, expectedPost.PublishedAt, expectedPost.PublishAt, expectedPost.CreatedAt, expectedPost.UpdatedAt, expectedPost.DeletedAt, expectedPost.Version))
+
+	expectPostRevision(mock, expectedPost.ID)
+	expectPostTaxonomy(mock)
+	mock.ExpectCommit()
 
 	updatedPost, err := repo.Update(context.Background(), id, updateInput)
 
@@ -213,6 +539,7 @@ func TestPostRepo_Update(t *testing.T) {
 	assert.Equal(t, expectedPost.ID, updatedPost.ID)
 	assert.Equal(t, expectedPost.Title, updatedPost.Title)
 	assert.Equal(t, expectedPost.Content, updatedPost.Content)
+	assert.Equal(t, expectedPost.Version, updatedPost.Version)
 	assert.WithinDuration(t, expectedPost.CreatedAt, updatedPost.CreatedAt, time.Second)
 	assert.WithinDuration(t, expectedPost.UpdatedAt, updatedPost.UpdatedAt, time.Second)
 
@@ -221,6 +548,275 @@ func TestPostRepo_Update(t *testing.T) {
 	}
 }
 
+func TestPostRepo_Update_Mask(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	id := 1
+	updateInput := &domain.PostInput{
+		Content: "Updated Content",
+		Version: 3,
+		Fields:  []string{domain.PostFieldContent},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE posts SET content = \\$1 WHERE id = \\$2 AND deleted_at IS NULL AND version = \\$3 RETURNING "+postColumns).
+		WithArgs(updateInput.Content, id, updateInput.Version).
+		WillReturnRows(newPostRows().
+			AddRow(id, "Title", updateInput.Content, 0, domain.PostStatusDraft, nil, nil, time.Now(), time.Now(), nil, 4))
+
+	expectPostRevision(mock, id)
+	expectPostTaxonomy(mock)
+	mock.ExpectCommit()
+
+	updatedPost, err := repo.Update(context.Background(), id, updateInput)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Title", updatedPost.Title)
+	assert.Equal(t, updateInput.Content, updatedPost.Content)
+	assert.Equal(t, 4, updatedPost.Version)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_Update_LinksOnly(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	id := 1
+	updateInput := &domain.PostInput{
+		Tags:    []string{},
+		Version: 3,
+		Fields:  []string{domain.PostFieldTags},
+	}
+
+	// The version is bumped but no revision is recorded
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE posts SET updated_at = NOW\\(\\) WHERE id = \\$1 AND deleted_at IS NULL AND version = \\$2 RETURNING "+postColumns).
+		WithArgs(id, updateInput.Version).
+		WillReturnRows(newPostRows().
+			AddRow(id, "Title", "Content", 0, domain.PostStatusDraft, nil, nil, time.Now(), time.Now(), nil, 4))
+	mock.ExpectExec("DELETE FROM post_tags WHERE post_id = \\$1").
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	expectPostTaxonomy(mock)
+	mock.ExpectCommit()
+
+	updatedPost, err := repo.Update(context.Background(), id, updateInput)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, updatedPost.Version)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_Update_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	id := 1
+	updateInput := &domain.PostInput{
+		Title:   "Updated Title",
+		Content: "Updated Content",
+		Version: 3,
+	}
+
+	// another editor got there first
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE posts SET title = \\$1, content = \\$2 WHERE id = \\$3 AND deleted_at IS NULL AND version = \\$4").
+		WithArgs(updateInput.Title, updateInput.Content, id, updateInput.Version).
+		WillReturnRows(newPostRows())
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM posts WHERE id = \\$1 AND deleted_at IS NULL\\)").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	_, err = repo.Update(context.Background(), id, updateInput)
+
+	assert.ErrorIs(t, err, errors.ErrVersionConflict)
+
+	// the post is gone altogether
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE posts SET title = \\$1, content = \\$2").
+		WithArgs(updateInput.Title, updateInput.Content, id, updateInput.Version).
+		WillReturnRows(newPostRows())
+	mock.ExpectQuery("SELECT EXISTS").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectRollback()
+
+	_, err = repo.Update(context.Background(), id, updateInput)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_List_Statuses(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	params := &domain.PostListParams{
+		Filter: domain.PostFilter{
+			AuthorID: 4,
+			Statuses: []domain.PostStatus{domain.PostStatusDraft, domain.PostStatusInReview},
+		},
+		Sort:  domain.PostSort{Field: domain.SortByCreatedAt, Desc: true},
+		Limit: 10,
+	}
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT "+postColumns+" FROM posts WHERE deleted_at IS NULL AND author_id = \\$1 AND status = ANY\\(\\$2\\) ORDER BY created_at DESC, id DESC LIMIT \\$3").
+		WithArgs(params.Filter.AuthorID, sqlmock.AnyArg(), params.Limit).
+		WillReturnRows(newPostRows().
+			AddRow(1, "Test Title", "Test Content", 4, "in_review", nil, nil, now, now, nil, 1))
+
+	mock.ExpectQuery("SELECT id, name, bio, created_at, updated_at FROM authors").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "bio", "created_at", "updated_at"}))
+	expectPostTaxonomy(mock)
+
+	postList, err := repo.List(context.Background(), params)
+
+	assert.NoError(t, err)
+	assert.Len(t, postList, 1)
+	assert.Equal(t, domain.PostStatusInReview, postList[0].Status)
+	assert.Nil(t, postList[0].PublishedAt)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_UpdateStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	id := 1
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE posts SET status = \\$3, (.+) WHERE id = \\$1 AND status = \\$2 AND deleted_at IS NULL RETURNING "+postColumns).
+		WithArgs(id, domain.PostStatusInReview, domain.PostStatusPublished).
+		WillReturnRows(newPostRows().
+			AddRow(id, "Test Title", "Test Content", 4, "published", now, nil, now, now, nil, 1))
+
+	mock.ExpectQuery("SELECT id, name, bio, created_at, updated_at FROM authors").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "bio", "created_at", "updated_at"}))
+	expectPostTaxonomy(mock)
+
+	post, err := repo.UpdateStatus(context.Background(), id, domain.PostStatusInReview, domain.PostStatusPublished)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PostStatusPublished, post.Status)
+	assert.NotNil(t, post.PublishedAt)
+
+	// the post has left the expected status in the meantime
+	mock.ExpectQuery("UPDATE posts SET status = \\$3").
+		WithArgs(id, domain.PostStatusInReview, domain.PostStatusPublished).
+		WillReturnRows(newPostRows())
+
+	_, err = repo.UpdateStatus(context.Background(), id, domain.PostStatusInReview, domain.PostStatusPublished)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_SetPublishAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	id := 1
+	now := time.Now()
+	publishAt := now.Add(time.Hour)
+
+	mock.ExpectQuery("UPDATE posts SET publish_at = \\$3 WHERE id = \\$1 AND status = \\$2 AND deleted_at IS NULL RETURNING "+postColumns).
+		WithArgs(id, domain.PostStatusInReview, &publishAt).
+		WillReturnRows(newPostRows().
+			AddRow(id, "Test Title", "Test Content", 0, "in_review", nil, publishAt, now, now, nil, 1))
+
+	expectPostTaxonomy(mock)
+
+	post, err := repo.SetPublishAt(context.Background(), id, domain.PostStatusInReview, &publishAt)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, post.PublishAt)
+	assert.WithinDuration(t, publishAt, *post.PublishAt, time.Second)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_PublishDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	now := time.Now()
+	limit := 10
+
+	mock.ExpectQuery("WITH due AS \\( SELECT id FROM posts WHERE publish_at <= \\$1 (.+) LIMIT \\$2 FOR UPDATE SKIP LOCKED \\) UPDATE posts SET status = 'published'").
+		WithArgs(now, limit).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(5))
+
+	ids, err := repo.PublishDue(context.Background(), now, limit)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 5}, ids)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
 func TestPostRepo_Delete(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -233,7 +829,7 @@ func TestPostRepo_Delete(t *testing.T) {
 	// Input data for testing
 	id := 1
 
-	mock.ExpectExec("^DELETE FROM posts WHERE id = ?").
+	mock.ExpectExec("^UPDATE posts SET deleted_at = NOW\\(\\) WHERE id = \\$1 AND deleted_at IS NULL").
 		WithArgs(id).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
@@ -0,0 +1,104 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+// ListRevisions returns the revisions of a post, newest first.
+func (r *PostRepo) ListRevisions(ctx context.Context, postID, limit, offset int) ([]*domain.PostRevision, error) {
+	query := `
+		SELECT id, post_id, revision, title, content, COALESCE(edited_by, 0), created_at
+		FROM post_revisions
+		WHERE post_id = $1
+		ORDER BY revision DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, postID, getQueryLimit(limit), offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*domain.PostRevision
+
+	for rows.Next() {
+		var revision domain.PostRevision
+		err := rows.Scan(
+			&revision.ID,
+			&revision.PostID,
+			&revision.Revision,
+			&revision.Title,
+			&revision.Content,
+			&revision.EditedBy,
+			&revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+func (r *PostRepo) GetRevision(ctx context.Context, postID, revision int) (*domain.PostRevision, error) {
+	query := `
+		SELECT id, post_id, revision, title, content, COALESCE(edited_by, 0), created_at
+		FROM post_revisions
+		WHERE post_id = $1 AND revision = $2
+	`
+
+	var res domain.PostRevision
+	err := r.db.QueryRowContext(ctx, query, postID, revision).Scan(
+		&res.ID,
+		&res.PostID,
+		&res.Revision,
+		&res.Title,
+		&res.Content,
+		&res.EditedBy,
+		&res.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// insertPostRevision records the current text of a post as its next
+// revision, unless the latest revision has the same text already. Callers
+// hold the post row lock, so revision numbers can not race.
+func insertPostRevision(ctx context.Context, tx *sqlx.Tx, post *domain.Post, editedBy int) error {
+	query := `
+		INSERT INTO post_revisions (post_id, revision, title, content, edited_by)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, NULLIF($4, 0)
+		FROM post_revisions
+		WHERE post_id = $1
+		HAVING NOT EXISTS (
+			SELECT 1
+			FROM (
+				SELECT title, content
+				FROM post_revisions
+				WHERE post_id = $1
+				ORDER BY revision DESC
+				LIMIT 1
+			) latest
+			WHERE latest.title = $2 AND latest.content = $3
+		)
+	`
+
+	_, err := tx.ExecContext(ctx, query, post.ID, post.Title, post.Content, editedBy)
+
+	return err
+}
@@ -0,0 +1,82 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/internal/repository/postgresql"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+func TestPostRepo_ListRevisions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	postID := 1
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT id, post_id, revision, title, content, COALESCE\\(edited_by, 0\\), created_at FROM post_revisions WHERE post_id = \\$1 ORDER BY revision DESC LIMIT \\$2 OFFSET \\$3").
+		WithArgs(postID, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "revision", "title", "content", "edited_by", "created_at"}).
+			AddRow(8, postID, 2, "Updated Title", "Updated Content", 4, now).
+			AddRow(5, postID, 1, "Test Title", "Test Content", 4, now.Add(-time.Hour)))
+
+	revisions, err := repo.ListRevisions(context.Background(), postID, 10, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, 2, revisions[0].Revision)
+	assert.Equal(t, "Test Title", revisions[1].Title)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_GetRevision(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	postID := 1
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM post_revisions WHERE post_id = \\$1 AND revision = \\$2").
+		WithArgs(postID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "revision", "title", "content", "edited_by", "created_at"}).
+			AddRow(5, postID, 1, "Test Title", "Test Content", 0, now))
+
+	revision, err := repo.GetRevision(context.Background(), postID, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, revision.ID)
+	assert.Equal(t, "Test Content", revision.Content)
+
+	mock.ExpectQuery("SELECT (.+) FROM post_revisions WHERE post_id = \\$1 AND revision = \\$2").
+		WithArgs(postID, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "revision", "title", "content", "edited_by", "created_at"}))
+
+	_, err = repo.GetRevision(context.Background(), postID, 9)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
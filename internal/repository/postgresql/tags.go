@@ -0,0 +1,139 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+type TagRepo struct {
+	db *sqlx.DB
+}
+
+func NewTagRepo(db *sqlx.DB) *TagRepo {
+	return &TagRepo{
+		db: db,
+	}
+}
+
+func (r *TagRepo) Get(ctx context.Context, id int) (*domain.Tag, error) {
+	query := `
+		SELECT id, name, slug, created_at
+		FROM tags
+		WHERE id = $1
+	`
+
+	var tag domain.Tag
+	err := r.db.QueryRowContext(ctx, query, id).
+		Scan(&tag.ID, &tag.Name, &tag.Slug, &tag.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (r *TagRepo) List(ctx context.Context, limit, offset int) ([]*domain.Tag, error) {
+	query := `
+		SELECT id, name, slug, created_at
+		FROM tags
+		ORDER BY name
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, getQueryLimit(limit), offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*domain.Tag
+
+	for rows.Next() {
+		var tag domain.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Slug, &tag.CreatedAt); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *TagRepo) Create(ctx context.Context, input *domain.TagInput) (*domain.Tag, error) {
+	query := `
+		INSERT INTO tags (name, slug)
+		VALUES ($1, $2)
+		RETURNING id, name, slug, created_at
+	`
+
+	var tag domain.Tag
+	err := r.db.QueryRowContext(ctx, query, input.Name, input.Slug).
+		Scan(&tag.ID, &tag.Name, &tag.Slug, &tag.CreatedAt)
+	if err != nil {
+		if isPgError(err, uniqueViolation) {
+			return nil, errors.ErrAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (r *TagRepo) Update(ctx context.Context, id int, input *domain.TagInput) (*domain.Tag, error) {
+	query := `
+		UPDATE tags
+		SET name = $1, slug = $2
+		WHERE id = $3
+		RETURNING id, name, slug, created_at
+	`
+
+	var tag domain.Tag
+	err := r.db.QueryRowContext(ctx, query, input.Name, input.Slug, id).
+		Scan(&tag.ID, &tag.Name, &tag.Slug, &tag.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+
+		if isPgError(err, uniqueViolation) {
+			return nil, errors.ErrAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (r *TagRepo) Delete(ctx context.Context, id int) (bool, error) {
+	query := `
+		DELETE FROM tags
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
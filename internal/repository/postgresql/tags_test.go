@@ -0,0 +1,62 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/internal/repository/postgresql"
+)
+
+func TestTagRepo_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewTagRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery("SELECT id, name, slug, created_at FROM tags ORDER BY name LIMIT \\$1 OFFSET \\$2").
+		WithArgs(10, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "slug", "created_at"}).
+			AddRow(1, "Elections", "elections", time.Now()).
+			AddRow(2, "Sport", "sport", time.Now()))
+
+	tags, err := repo.List(context.Background(), 10, 20)
+
+	assert.NoError(t, err)
+	assert.Len(t, tags, 2)
+	assert.Equal(t, "elections", tags[0].Slug)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTagRepo_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewTagRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec("^DELETE FROM tags WHERE id = ?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ok, err := repo.Delete(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
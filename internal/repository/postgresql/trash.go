@@ -0,0 +1,113 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/kokhno-nikolay/news/domain"
+)
+
+// GetTrashed returns a post from the trash.
+func (r *PostRepo) GetTrashed(ctx context.Context, id int) (*domain.Post, error) {
+	return r.get(ctx, id, true)
+}
+
+// ListTrash returns trashed posts, most recently deleted first. A non-zero
+// authorID narrows the list to the posts of that author.
+func (r *PostRepo) ListTrash(ctx context.Context, authorID, limit, offset int) ([]*domain.Post, error) {
+	query := `
+		SELECT ` + postColumns + `
+		FROM posts
+		WHERE deleted_at IS NOT NULL AND ($1 = 0 OR author_id = $1)
+		ORDER BY deleted_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, authorID, getQueryLimit(limit), offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var postList []*domain.Post
+
+	for rows.Next() {
+		var post domain.Post
+		if err := scanPost(rows, &post); err != nil {
+			return nil, err
+		}
+		postList = append(postList, &post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadPostRelations(ctx, r.db, postList); err != nil {
+		return nil, err
+	}
+
+	return postList, nil
+}
+
+// Restore takes a post out of the trash.
+func (r *PostRepo) Restore(ctx context.Context, id int) (*domain.Post, error) {
+	query := `
+		UPDATE posts
+		SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING ` + postColumns + `
+	`
+
+	return r.updatePost(ctx, query, id)
+}
+
+// Purge permanently removes a trashed post together with its revisions and
+// links.
+func (r *PostRepo) Purge(ctx context.Context, id int) (bool, error) {
+	query := `
+		DELETE FROM posts
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// PurgeTrashed permanently removes up to limit posts trashed before the given
+// moment and returns how many were removed. Rows locked by another replica
+// are skipped.
+func (r *PostRepo) PurgeTrashed(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `
+		DELETE FROM posts
+		WHERE id IN (
+			SELECT id
+			FROM posts
+			WHERE deleted_at < $1
+			ORDER BY deleted_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, before, getQueryLimit(limit))
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
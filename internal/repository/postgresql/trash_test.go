@@ -0,0 +1,134 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/internal/repository/postgresql"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+func TestPostRepo_ListTrash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT "+postColumns+" FROM posts WHERE deleted_at IS NOT NULL AND \\(\\$1 = 0 OR author_id = \\$1\\) ORDER BY deleted_at DESC, id DESC LIMIT \\$2 OFFSET \\$3").
+		WithArgs(0, 10, 0).
+		WillReturnRows(newPostRows().
+			AddRow(1, "Test Title", "Test Content", 0, "draft", nil, nil, now, now, now, 1))
+
+	expectPostTaxonomy(mock)
+
+	posts, err := repo.ListTrash(context.Background(), 0, 10, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, posts, 1)
+	assert.NotNil(t, posts[0].DeletedAt)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	id := 1
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE posts SET deleted_at = NULL WHERE id = \\$1 AND deleted_at IS NOT NULL RETURNING " + postColumns).
+		WithArgs(id).
+		WillReturnRows(newPostRows().
+			AddRow(id, "Test Title", "Test Content", 0, "draft", nil, nil, now, now, nil, 1))
+
+	expectPostTaxonomy(mock)
+
+	post, err := repo.Restore(context.Background(), id)
+
+	assert.NoError(t, err)
+	assert.Nil(t, post.DeletedAt)
+
+	// the post is not in the trash
+	mock.ExpectQuery("UPDATE posts SET deleted_at = NULL").
+		WithArgs(id).
+		WillReturnRows(newPostRows())
+
+	_, err = repo.Restore(context.Background(), id)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_Purge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	id := 1
+
+	mock.ExpectExec("^DELETE FROM posts WHERE id = \\$1 AND deleted_at IS NOT NULL").
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ok, err := repo.Purge(context.Background(), id)
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostRepo_PurgeTrashed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewPostRepo(sqlx.NewDb(db, "sqlmock"))
+
+	// Input data for testing
+	before := time.Now().Add(-30 * 24 * time.Hour)
+
+	mock.ExpectExec("DELETE FROM posts WHERE id IN \\( SELECT id FROM posts WHERE deleted_at < \\$1 ORDER BY deleted_at LIMIT \\$2 FOR UPDATE SKIP LOCKED \\)").
+		WithArgs(before, 100).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	purged, err := repo.PurgeTrashed(context.Background(), before, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, purged)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
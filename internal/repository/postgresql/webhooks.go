@@ -0,0 +1,355 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+const webhookDeliveryColumns = `id, subscription_id, COALESCE(event_id, 0), event, payload, status, attempts,
+	COALESCE(response_status, 0), COALESCE(last_error, ''), created_at, delivered_at`
+
+// WebhookRepo stores webhook subscriptions and the deliveries made to them.
+type WebhookRepo struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepo(db *sqlx.DB) *WebhookRepo {
+	return &WebhookRepo{
+		db: db,
+	}
+}
+
+func (r *WebhookRepo) Get(ctx context.Context, id int) (*domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, secret, events, COALESCE(created_by, 0), created_at
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+
+	subscription, err := scanWebhookSubscription(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// List returns the subscriptions, oldest first.
+func (r *WebhookRepo) List(ctx context.Context, limit, offset int) ([]*domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, secret, events, COALESCE(created_by, 0), created_at
+		FROM webhook_subscriptions
+		ORDER BY id
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, getQueryLimit(limit), offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*domain.WebhookSubscription
+
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *WebhookRepo) Create(ctx context.Context, input *domain.WebhookInput) (*domain.WebhookSubscription, error) {
+	query := `
+		INSERT INTO webhook_subscriptions (url, secret, events, created_by)
+		VALUES ($1, $2, $3, NULLIF($4, 0))
+		RETURNING id, url, secret, events, COALESCE(created_by, 0), created_at
+	`
+
+	events := make([]string, len(input.Events))
+	for i, event := range input.Events {
+		events[i] = string(event)
+	}
+
+	return scanWebhookSubscription(r.db.QueryRowContext(ctx, query, input.URL, input.Secret, pq.Array(events), input.CreatedBy))
+}
+
+// Delete removes the subscription along with its delivery log.
+func (r *WebhookRepo) Delete(ctx context.Context, id int) (bool, error) {
+	query := `
+		DELETE FROM webhook_subscriptions
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Enqueue queues a delivery of the payload to every subscription of the
+// event and returns how many were queued. Enqueueing the same post event
+// again queues nothing.
+func (r *WebhookRepo) Enqueue(ctx context.Context, eventID int64, event domain.WebhookEvent, payload json.RawMessage) (int, error) {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event, payload)
+		SELECT id, $1, $2, $3
+		FROM webhook_subscriptions
+		WHERE $2 = ANY(events)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, eventID, event, []byte(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
+
+// CreateDelivery records a delivery that is sent right away rather than by
+// the workers, they leave it alone for the lease.
+func (r *WebhookRepo) CreateDelivery(ctx context.Context, subscriptionID int, event domain.WebhookEvent, payload json.RawMessage, lease time.Duration) (*domain.WebhookDelivery, error) {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event, payload, attempts, next_attempt_at)
+		VALUES ($1, $2, $3, 1, NOW() + make_interval(secs => $4))
+		RETURNING ` + webhookDeliveryColumns + `
+	`
+
+	delivery, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, subscriptionID, event, []byte(payload), lease.Seconds()))
+	if err != nil {
+		if isPgError(err, foreignKeyViolation) {
+			return nil, errors.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// ListDeliveries returns the delivery log of a subscription, latest first.
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, subscriptionID, limit, offset int) ([]*domain.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, subscriptionID, getQueryLimit(limit), offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDeliveries takes up to limit pending deliveries that are due, oldest
+// first, along with the URL and secret of their subscription. Like the
+// outbox, they are hidden from other workers for the lease.
+func (r *WebhookRepo) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1,
+			next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM webhook_subscriptions s
+		WHERE s.id = d.subscription_id AND d.id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING d.id, d.subscription_id, COALESCE(d.event_id, 0), d.event, d.payload, d.attempts, d.created_at, s.url, s.secret
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, getQueryLimit(limit), lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+
+	for rows.Next() {
+		delivery := domain.WebhookDelivery{Status: domain.WebhookDeliveryPending}
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.SubscriptionID,
+			&delivery.EventID,
+			&delivery.Event,
+			&delivery.Payload,
+			&delivery.Attempts,
+			&delivery.CreatedAt,
+			&delivery.URL,
+			&delivery.Secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING follows no particular order
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID < deliveries[j].ID })
+
+	return deliveries, nil
+}
+
+// MarkDelivered records the successful attempt of a delivery.
+func (r *WebhookRepo) MarkDelivered(ctx context.Context, id int64, responseStatus int) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', response_status = $2, last_error = NULL, delivered_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, responseStatus)
+
+	return err
+}
+
+// MarkFailed records a failed attempt of a delivery. It is retried at
+// retryAt, a nil retryAt gives it up.
+func (r *WebhookRepo) MarkFailed(ctx context.Context, id int64, responseStatus int, reason string, retryAt *time.Time) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET response_status = NULLIF($2, 0),
+			last_error = $3,
+			next_attempt_at = COALESCE($4, next_attempt_at),
+			status = CASE WHEN $4::timestamptz IS NULL THEN 'failed' ELSE 'pending' END
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, responseStatus, reason, retryAt)
+
+	return err
+}
+
+// PurgeDeliveries removes up to limit deliveries created before the given
+// time that are no longer pending and returns how many were removed.
+func (r *WebhookRepo) PurgeDeliveries(ctx context.Context, before time.Time, limit int) (int, error) {
+	query := `
+		DELETE FROM webhook_deliveries
+		WHERE id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE created_at < $1 AND status <> 'pending'
+			ORDER BY id
+			LIMIT $2
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, before, getQueryLimit(limit))
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
+
+func scanWebhookSubscription(row rowScanner) (*domain.WebhookSubscription, error) {
+	var subscription domain.WebhookSubscription
+	var events []string
+
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.URL,
+		&subscription.Secret,
+		pq.Array(&events),
+		&subscription.CreatedBy,
+		&subscription.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Events = make([]domain.WebhookEvent, len(events))
+	for i, event := range events {
+		subscription.Events[i] = domain.WebhookEvent(event)
+	}
+
+	return &subscription, nil
+}
+
+func scanWebhookDelivery(row rowScanner) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventID,
+		&delivery.Event,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseStatus,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&delivery.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
@@ -0,0 +1,186 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/repository/postgresql"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+func TestWebhookRepo_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewWebhookRepo(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+	input := &domain.WebhookInput{
+		URL:       "https://example.com/hook",
+		Events:    []domain.WebhookEvent{domain.WebhookPostPublished, domain.WebhookPostDeleted},
+		Secret:    "secret",
+		CreatedBy: 3,
+	}
+
+	mock.ExpectQuery("INSERT INTO webhook_subscriptions \\(url, secret, events, created_by\\) VALUES \\(\\$1, \\$2, \\$3, NULLIF\\(\\$4, 0\\)\\)").
+		WithArgs(input.URL, input.Secret, pq.Array([]string{"post.published", "post.deleted"}), 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "secret", "events", "created_by", "created_at"}).
+			AddRow(1, input.URL, input.Secret, "{post.published,post.deleted}", 3, now))
+
+	subscription, err := repo.Create(context.Background(), input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, subscription.ID)
+	assert.Equal(t, input.Events, subscription.Events)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestWebhookRepo_Get_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewWebhookRepo(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery("SELECT id, url, secret, events, COALESCE\\(created_by, 0\\), created_at FROM webhook_subscriptions WHERE id = \\$1").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "secret", "events", "created_by", "created_at"}))
+
+	_, err = repo.Get(context.Background(), 1)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestWebhookRepo_Enqueue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewWebhookRepo(sqlx.NewDb(db, "sqlmock"))
+
+	payload := []byte(`{"event":"post.published"}`)
+
+	mock.ExpectExec("INSERT INTO webhook_deliveries \\(subscription_id, event_id, event, payload\\) SELECT id, \\$1, \\$2, \\$3 FROM webhook_subscriptions WHERE \\$2 = ANY\\(events\\) ON CONFLICT \\(subscription_id, event_id\\) DO NOTHING").
+		WithArgs(int64(7), domain.WebhookPostPublished, payload).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	queued, err := repo.Enqueue(context.Background(), 7, domain.WebhookPostPublished, payload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, queued)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestWebhookRepo_ClaimDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewWebhookRepo(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+
+	mock.ExpectQuery("UPDATE webhook_deliveries d SET attempts = d.attempts \\+ 1, next_attempt_at = NOW\\(\\) \\+ make_interval\\(secs => \\$2\\) FROM webhook_subscriptions s WHERE s.id = d.subscription_id AND d.id IN \\( SELECT id FROM webhook_deliveries WHERE status = 'pending' AND next_attempt_at <= NOW\\(\\) ORDER BY id LIMIT \\$1 FOR UPDATE SKIP LOCKED \\)").
+		WithArgs(50, float64(300)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "event_id", "event", "payload", "attempts", "created_at", "url", "secret"}).
+			AddRow(9, 2, 7, "post.published", []byte(`{}`), 3, now, "https://b.example.com", "b").
+			AddRow(8, 1, 7, "post.published", []byte(`{}`), 1, now, "https://a.example.com", "a"))
+
+	deliveries, err := repo.ClaimDeliveries(context.Background(), 50, 5*time.Minute)
+
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.Equal(t, int64(8), deliveries[0].ID)
+	assert.Equal(t, "https://a.example.com", deliveries[0].URL)
+	assert.Equal(t, "b", deliveries[1].Secret)
+	assert.Equal(t, 3, deliveries[1].Attempts)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestWebhookRepo_MarkFailed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewWebhookRepo(sqlx.NewDb(db, "sqlmock"))
+
+	retryAt := time.Now().Add(time.Minute)
+
+	mock.ExpectExec("UPDATE webhook_deliveries SET response_status = NULLIF\\(\\$2, 0\\), last_error = \\$3, next_attempt_at = COALESCE\\(\\$4, next_attempt_at\\)").
+		WithArgs(int64(8), 503, "webhook responded with 503", retryAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// without a retry the delivery is given up
+	mock.ExpectExec("UPDATE webhook_deliveries SET response_status = NULLIF\\(\\$2, 0\\)").
+		WithArgs(int64(8), 0, "connection refused", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MarkFailed(context.Background(), 8, 503, "webhook responded with 503", &retryAt))
+	assert.NoError(t, repo.MarkFailed(context.Background(), 8, 0, "connection refused", nil))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestWebhookRepo_ListDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := postgresql.NewWebhookRepo(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT id, subscription_id, COALESCE\\(event_id, 0\\), event, payload, status, attempts, COALESCE\\(response_status, 0\\), COALESCE\\(last_error, ''\\), created_at, delivered_at FROM webhook_deliveries WHERE subscription_id = \\$1 ORDER BY id DESC LIMIT \\$2 OFFSET \\$3").
+		WithArgs(1, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "event_id", "event", "payload", "status", "attempts", "response_status", "last_error", "created_at", "delivered_at"}).
+			AddRow(9, 1, 0, "webhook.test", []byte(`{}`), "delivered", 1, 204, "", now, now).
+			AddRow(8, 1, 7, "post.published", []byte(`{}`), "failed", 10, 0, "connection refused", now, nil))
+
+	deliveries, err := repo.ListDeliveries(context.Background(), 1, 10, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.Equal(t, domain.WebhookDeliveryDelivered, deliveries[0].Status)
+	assert.NotNil(t, deliveries[0].DeliveredAt)
+	assert.Equal(t, "connection refused", deliveries[1].LastError)
+	assert.Nil(t, deliveries[1].DeliveredAt)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
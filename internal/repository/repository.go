@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -13,18 +15,106 @@ import (
 
 type Posts interface {
 	Get(ctx context.Context, id int) (*domain.Post, error)
-	List(ctx context.Context, limit *int) ([]*domain.Post, error)
+	List(ctx context.Context, params *domain.PostListParams) ([]*domain.Post, error)
+	Count(ctx context.Context, filter *domain.PostFilter) (int, error)
+	Search(ctx context.Context, params *domain.SearchParams) ([]*domain.SearchResult, error)
 	Create(ctx context.Context, input *domain.PostInput) (*domain.Post, error)
 	Update(ctx context.Context, id int, input *domain.PostInput) (*domain.Post, error)
+	UpdateStatus(ctx context.Context, id int, from, to domain.PostStatus) (*domain.Post, error)
+	SetPublishAt(ctx context.Context, id int, status domain.PostStatus, publishAt *time.Time) (*domain.Post, error)
+	PublishDue(ctx context.Context, now time.Time, limit int) ([]int, error)
+	ListRevisions(ctx context.Context, postID, limit, offset int) ([]*domain.PostRevision, error)
+	GetRevision(ctx context.Context, postID, revision int) (*domain.PostRevision, error)
+	GetTrashed(ctx context.Context, id int) (*domain.Post, error)
+	ListTrash(ctx context.Context, authorID, limit, offset int) ([]*domain.Post, error)
+	Restore(ctx context.Context, id int) (*domain.Post, error)
+	Purge(ctx context.Context, id int) (bool, error)
+	PurgeTrashed(ctx context.Context, before time.Time, limit int) (int, error)
 	Delete(ctx context.Context, id int) (bool, error)
+	GetBatch(ctx context.Context, ids []int) ([]*domain.Post, error)
+	CreateBatch(ctx context.Context, inputs []*domain.PostInput) ([]*domain.Post, error)
+	UpdateBatch(ctx context.Context, updates []*domain.PostBatchUpdate) ([]*domain.Post, error)
+	DeleteBatch(ctx context.Context, ids []int) ([]int, error)
+	ListEvents(ctx context.Context, afterID int64, limit int) ([]*domain.PostEvent, error)
+	EventBounds(ctx context.Context) (first, last int64, err error)
+	EventSnapshot(ctx context.Context) (xmin, xmax int64, err error)
+	PurgeEvents(ctx context.Context, before time.Time, limit int) (int, error)
 }
 
+// Outbox hands post events over to the relay delivering them.
+type Outbox interface {
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.PostEvent, error)
+	MarkDelivered(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, reason string, retryAt *time.Time) error
+}
+
+// Webhooks stores webhook subscriptions and queues their deliveries.
+type Webhooks interface {
+	Get(ctx context.Context, id int) (*domain.WebhookSubscription, error)
+	List(ctx context.Context, limit, offset int) ([]*domain.WebhookSubscription, error)
+	Create(ctx context.Context, input *domain.WebhookInput) (*domain.WebhookSubscription, error)
+	Delete(ctx context.Context, id int) (bool, error)
+	Enqueue(ctx context.Context, eventID int64, event domain.WebhookEvent, payload json.RawMessage) (int, error)
+	CreateDelivery(ctx context.Context, subscriptionID int, event domain.WebhookEvent, payload json.RawMessage, lease time.Duration) (*domain.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID, limit, offset int) ([]*domain.WebhookDelivery, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, responseStatus int) error
+	MarkFailed(ctx context.Context, id int64, responseStatus int, reason string, retryAt *time.Time) error
+	PurgeDeliveries(ctx context.Context, before time.Time, limit int) (int, error)
+}
+
+// PostNotifier tells when new post events have been recorded.
+type PostNotifier interface {
+	Changed() <-chan struct{}
+}
+
+type Categories interface {
+	Get(ctx context.Context, id int) (*domain.Category, error)
+	List(ctx context.Context) ([]*domain.Category, error)
+	Create(ctx context.Context, input *domain.CategoryInput) (*domain.Category, error)
+	Update(ctx context.Context, id int, input *domain.CategoryInput) (*domain.Category, error)
+	Delete(ctx context.Context, id int) (bool, error)
+}
+
+type Tags interface {
+	Get(ctx context.Context, id int) (*domain.Tag, error)
+	List(ctx context.Context, limit, offset int) ([]*domain.Tag, error)
+	Create(ctx context.Context, input *domain.TagInput) (*domain.Tag, error)
+	Update(ctx context.Context, id int, input *domain.TagInput) (*domain.Tag, error)
+	Delete(ctx context.Context, id int) (bool, error)
+}
+
+type Authors interface {
+	Get(ctx context.Context, id int) (*domain.Author, error)
+	List(ctx context.Context, limit, offset int) ([]*domain.Author, error)
+	Create(ctx context.Context, input *domain.AuthorInput) (*domain.Author, error)
+	Update(ctx context.Context, id int, input *domain.AuthorInput) (*domain.Author, error)
+	Delete(ctx context.Context, id int) (bool, error)
+}
+
+// MaxLimit is the most rows a repository call returns at once. Workers
+// draining a queue batch by batch must not ask for more, or a capped batch
+// looks like the last one.
+const MaxLimit = postgresql.MaxLimit
+
 type Repository struct {
 	Posts
+	Categories
+	Tags
+	Authors
+	Outbox
+	Webhooks Webhooks
+	Notifier PostNotifier
 }
 
-func NewRepository(db *sqlx.DB) *Repository {
+func NewRepository(db *sqlx.DB, notifier PostNotifier) *Repository {
 	return &Repository{
-		Posts: postgresql.NewPostRepo(db),
+		Posts:      postgresql.NewPostRepo(db),
+		Categories: postgresql.NewCategoryRepo(db),
+		Tags:       postgresql.NewTagRepo(db),
+		Authors:    postgresql.NewAuthorRepo(db),
+		Outbox:     postgresql.NewOutboxRepo(db),
+		Webhooks:   postgresql.NewWebhookRepo(db),
+		Notifier:   notifier,
 	}
 }
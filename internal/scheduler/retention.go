@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/internal/repository"
+)
+
+const (
+	defaultPurgeInterval = time.Hour
+	purgeBatchSize       = 100
+	// eventsPurgeBatchSize is the most rows a repository query handles
+	eventsPurgeBatchSize = 200
+)
+
+// Retention periodically purges posts that have stayed in the trash longer
+// than the retention period, along with old post events and webhook
+// deliveries. Like Scheduler it is safe to run on every replica.
+type Retention struct {
+	repo             repository.Posts
+	webhooks         repository.Webhooks
+	period           time.Duration
+	eventsPeriod     time.Duration
+	deliveriesPeriod time.Duration
+	interval         time.Duration
+}
+
+func NewRetention(repo repository.Posts, webhooks repository.Webhooks, cfg *config.Config) *Retention {
+	r := &Retention{
+		repo:             repo,
+		webhooks:         webhooks,
+		period:           time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour,
+		eventsPeriod:     cfg.PostEventsRetention,
+		deliveriesPeriod: cfg.WebhookDeliveriesRetention,
+		interval:         cfg.TrashPurgeInterval,
+	}
+
+	if r.interval <= 0 {
+		r.interval = defaultPurgeInterval
+	}
+
+	return r
+}
+
+// Run purges expired posts, events and deliveries every interval until ctx
+// is done. It returns at once when all retentions are disabled.
+func (r *Retention) Run(ctx context.Context) {
+	if r.period <= 0 && r.eventsPeriod <= 0 && r.deliveriesPeriod <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if r.period > 0 {
+			r.purgeExpired(ctx)
+		}
+
+		if r.eventsPeriod > 0 {
+			r.purgeExpiredEvents(ctx)
+		}
+
+		if r.deliveriesPeriod > 0 {
+			r.purgeExpiredDeliveries(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Retention) purgeExpiredEvents(ctx context.Context) {
+	for ctx.Err() == nil {
+		purged, err := r.repo.PurgeEvents(ctx, time.Now().Add(-r.eventsPeriod), eventsPurgeBatchSize)
+		if err != nil {
+			logrus.WithError(err).Error("retention: purging post events")
+			return
+		}
+
+		if purged < eventsPurgeBatchSize {
+			return
+		}
+	}
+}
+
+func (r *Retention) purgeExpiredDeliveries(ctx context.Context) {
+	for ctx.Err() == nil {
+		purged, err := r.webhooks.PurgeDeliveries(ctx, time.Now().Add(-r.deliveriesPeriod), eventsPurgeBatchSize)
+		if err != nil {
+			logrus.WithError(err).Error("retention: purging webhook deliveries")
+			return
+		}
+
+		if purged < eventsPurgeBatchSize {
+			return
+		}
+	}
+}
+
+func (r *Retention) purgeExpired(ctx context.Context) {
+	for ctx.Err() == nil {
+		purged, err := r.repo.PurgeTrashed(ctx, time.Now().Add(-r.period), purgeBatchSize)
+		if err != nil {
+			logrus.WithError(err).Error("retention: purging trashed posts")
+			return
+		}
+
+		if purged > 0 {
+			logrus.WithField("count", purged).Info("retention: purged posts")
+		}
+
+		if purged < purgeBatchSize {
+			return
+		}
+	}
+}
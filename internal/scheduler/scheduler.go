@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/internal/repository"
+)
+
+const (
+	defaultInterval  = 30 * time.Second
+	defaultBatchSize = 100
+)
+
+// Scheduler periodically publishes posts whose publish_at has come. Every
+// replica may run one, the repository skips posts locked by the others.
+type Scheduler struct {
+	repo      repository.Posts
+	interval  time.Duration
+	batchSize int
+}
+
+func NewScheduler(repo repository.Posts, cfg *config.Config) *Scheduler {
+	s := &Scheduler{
+		repo:      repo,
+		interval:  cfg.SchedulerInterval,
+		batchSize: cfg.SchedulerBatchSize,
+	}
+
+	if s.interval <= 0 {
+		s.interval = defaultInterval
+	}
+
+	if s.batchSize <= 0 {
+		s.batchSize = defaultBatchSize
+	}
+
+	if s.batchSize > repository.MaxLimit {
+		s.batchSize = repository.MaxLimit
+	}
+
+	return s
+}
+
+// Run publishes due posts every interval until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.publishDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishDue drains all due posts batch by batch, a full batch means more
+// posts may be waiting.
+func (s *Scheduler) publishDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		ids, err := s.repo.PublishDue(ctx, time.Now(), s.batchSize)
+		if err != nil {
+			logrus.WithError(err).Error("scheduler: publishing due posts")
+			return
+		}
+
+		if len(ids) > 0 {
+			logrus.WithField("post_ids", ids).Info("scheduler: published posts")
+		}
+
+		if len(ids) < s.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/internal/repository"
+	mock_repository "github.com/kokhno-nikolay/news/internal/repository/mocks"
+)
+
+func TestScheduler_PublishDue(t *testing.T) {
+	t.Run("drains all pages", func(t *testing.T) {
+		posts := mock_repository.NewMockPosts(gomock.NewController(t))
+		s := NewScheduler(posts, &config.Config{SchedulerBatchSize: 2})
+
+		gomock.InOrder(
+			posts.EXPECT().PublishDue(gomock.Any(), gomock.Any(), 2).Return([]int{1, 2}, nil),
+			posts.EXPECT().PublishDue(gomock.Any(), gomock.Any(), 2).Return([]int{3, 4}, nil),
+			posts.EXPECT().PublishDue(gomock.Any(), gomock.Any(), 2).Return([]int{5}, nil),
+		)
+
+		s.publishDue(context.Background())
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		posts := mock_repository.NewMockPosts(gomock.NewController(t))
+		s := NewScheduler(posts, &config.Config{SchedulerBatchSize: 2})
+
+		posts.EXPECT().PublishDue(gomock.Any(), gomock.Any(), 2).Return(nil, errors.New("connection refused"))
+
+		s.publishDue(context.Background())
+	})
+
+	t.Run("batch size above the repository cap", func(t *testing.T) {
+		posts := mock_repository.NewMockPosts(gomock.NewController(t))
+		s := NewScheduler(posts, &config.Config{SchedulerBatchSize: 1000})
+
+		// a full capped page is not mistaken for the last one
+		gomock.InOrder(
+			posts.EXPECT().PublishDue(gomock.Any(), gomock.Any(), repository.MaxLimit).Return(make([]int, repository.MaxLimit), nil),
+			posts.EXPECT().PublishDue(gomock.Any(), gomock.Any(), repository.MaxLimit).Return(nil, nil),
+		)
+
+		s.publishDue(context.Background())
+	})
+}
+
+func TestRetention_Run_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r := NewRetention(mock_repository.NewMockPosts(ctrl), mock_repository.NewMockWebhooks(ctrl), &config.Config{})
+
+	done := make(chan struct{})
+	go func() {
+		r.Run(context.Background())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return with every retention disabled")
+	}
+}
+
+func TestRetention_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        *config.Config
+		trash      bool
+		events     bool
+		deliveries bool
+	}{
+		{name: "trash", cfg: &config.Config{TrashRetentionDays: 30}, trash: true},
+		{name: "events", cfg: &config.Config{PostEventsRetention: time.Hour}, events: true},
+		{name: "deliveries", cfg: &config.Config{WebhookDeliveriesRetention: time.Hour}, deliveries: true},
+		{
+			name:       "all",
+			cfg:        &config.Config{TrashRetentionDays: 30, PostEventsRetention: time.Hour, WebhookDeliveriesRetention: time.Hour},
+			trash:      true,
+			events:     true,
+			deliveries: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			posts := mock_repository.NewMockPosts(ctrl)
+			webhooks := mock_repository.NewMockWebhooks(ctrl)
+			r := NewRetention(posts, webhooks, tt.cfg)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// every kind is purged once, the last one set ends the run
+			var calls []*gomock.Call
+			if tt.trash {
+				calls = append(calls, posts.EXPECT().PurgeTrashed(gomock.Any(), gomock.Any(), purgeBatchSize).Return(0, nil))
+			}
+			if tt.events {
+				calls = append(calls, posts.EXPECT().PurgeEvents(gomock.Any(), gomock.Any(), eventsPurgeBatchSize).Return(0, nil))
+			}
+			if tt.deliveries {
+				calls = append(calls, webhooks.EXPECT().PurgeDeliveries(gomock.Any(), gomock.Any(), eventsPurgeBatchSize).Return(0, nil))
+			}
+			calls[len(calls)-1].Do(func(context.Context, time.Time, int) { cancel() })
+			gomock.InOrder(calls...)
+
+			r.Run(ctx)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kokhno-nikolay/news/internal/auth"
+)
+
+const authorizationHeader = "authorization"
+
+// authInterceptor verifies the bearer token from the authorization metadata
+// and puts the caller on the context. Public methods may be called without a
+// token, but a token that is sent must still be valid.
+func authInterceptor(verifier *auth.Verifier, publicMethods []string) grpc.UnaryServerInterceptor {
+	authenticate := authenticator(verifier, publicMethods)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// authStreamInterceptor is authInterceptor for streaming RPCs.
+func authStreamInterceptor(verifier *auth.Verifier, publicMethods []string) grpc.StreamServerInterceptor {
+	authenticate := authenticator(verifier, publicMethods)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticator returns a function that puts the caller of a method on the
+// context.
+func authenticator(verifier *auth.Verifier, publicMethods []string) func(ctx context.Context, method string) (context.Context, error) {
+	public := make(map[string]bool, len(publicMethods))
+	for _, method := range publicMethods {
+		public[strings.TrimSpace(method)] = true
+	}
+
+	return func(ctx context.Context, method string) (context.Context, error) {
+		token, ok := bearerToken(ctx)
+		if !ok {
+			if public[method] {
+				return ctx, nil
+			}
+
+			return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		}
+
+		claims, err := verifier.Verify(token)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+		}
+
+		return auth.WithActor(ctx, claims.Actor()), nil
+	}
+}
+
+// contextStream replaces the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func bearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	values := md.Get(authorizationHeader)
+	if len(values) == 0 {
+		return "", false
+	}
+
+	scheme, token, found := strings.Cut(values[0], " ")
+	if !found || !strings.EqualFold(scheme, "bearer") || token == "" {
+		return "", false
+	}
+
+	return strings.TrimSpace(token), true
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+
+	proto "github.com/kokhno-nikolay/news/api/proto"
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/auth"
+	"github.com/kokhno-nikolay/news/internal/service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type AuthorsServer struct {
+	proto.UnimplementedAuthorsServer
+	authorService service.AuthorService
+}
+
+func NewAuthorsServer(authorService service.AuthorService) *AuthorsServer {
+	return &AuthorsServer{
+		authorService: authorService,
+	}
+}
+
+// @Summary		Get author
+// @Description	Getting author entity by id.
+// @Tags		authors
+// @Accept		json
+// @Produce		json
+// @Param		id       query      int true  "Author ID"
+// @Success		200      {object}   domain.Author
+// @Failure		400,404  {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/authors [get]
+func (s *AuthorsServer) GetAuthor(ctx context.Context, req *proto.GetAuthorRequest) (*proto.Author, error) {
+	author, err := s.authorService.Get(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertAuthorForCaller(ctx, author), nil
+}
+
+// @Summary		Get author list
+// @Description	Getting authors ordered by name
+// @Tags		authors
+// @Accept		json
+// @Produce		json
+// @Param		limit    query      int     false  "Page size"
+// @Param		offset   query      int     false  "Offset"
+// @Success		200      {array}    domain.Author
+// @Failure		400      {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/authors/list [get]
+func (s *AuthorsServer) ListAuthors(ctx context.Context, req *proto.ListAuthorsRequest) (*proto.ListAuthorsResponse, error) {
+	res, err := s.authorService.List(ctx, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, err
+	}
+
+	authors := make([]*proto.Author, 0)
+	for _, item := range res {
+		authors = append(authors, convertAuthorForCaller(ctx, item))
+	}
+
+	return &proto.ListAuthorsResponse{
+		Authors: authors,
+	}, nil
+}
+
+// @Summary		Create author
+// @Description	Creates a new author, editors only
+// @Tags		authors
+// @Accept		json
+// @Produce		json
+// @Param		input       body        domain.AuthorInput  true  "Author"
+// @Success		201         {object}    domain.Author
+// @Failure		400,403,409 {object}    errorResponse
+// @Failure		500         {object}    errorResponse
+// @Failure		default     {object}    errorResponse
+// @Router		/authors [post]
+func (s *AuthorsServer) CreateAuthor(ctx context.Context, req *proto.CreateAuthorRequest) (*proto.Author, error) {
+	res, err := s.authorService.Create(ctx, domain.AuthorInput{
+		Name:  req.Name,
+		Email: req.Email,
+		Bio:   req.Bio,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertAuthorForCaller(ctx, res), nil
+}
+
+// @Summary		Update author
+// @Description	Updating author profile, by the author or an editor
+// @Tags		authors
+// @Accept		json
+// @Produce		json
+// @Param		input           body        domain.AuthorInput  true  "Author"
+// @Success		200             {object}    domain.Author
+// @Failure		400,403,404,409 {object}    errorResponse
+// @Failure		500             {object}    errorResponse
+// @Failure		default         {object}    errorResponse
+// @Router		/authors [patch]
+func (s *AuthorsServer) UpdateAuthor(ctx context.Context, req *proto.UpdateAuthorRequest) (*proto.Author, error) {
+	res, err := s.authorService.Update(ctx, int(req.Id), domain.AuthorInput{
+		Name:  req.Name,
+		Email: req.Email,
+		Bio:   req.Bio,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertAuthorForCaller(ctx, res), nil
+}
+
+// @Summary		Delete author
+// @Description	Deleting author entity, editors only. Posts of the author are kept.
+// @Tags		authors
+// @Accept		json
+// @Produce		json
+// @Param		id          query       int true   "Author ID"
+// @Success		200         {bool}      true
+// @Failure		400,403,404 {object}    errorResponse
+// @Failure		500         {object}    errorResponse
+// @Failure		default     {object}    errorResponse
+// @Router		/authors [delete]
+func (s *AuthorsServer) DeleteAuthor(ctx context.Context, req *proto.DeleteAuthorRequest) (*proto.DeleteResponse, error) {
+	success, err := s.authorService.Delete(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.DeleteResponse{
+		Success: success,
+	}, nil
+}
+
+// convertAuthorForCaller converts an author with the email only when the
+// caller is that author or an editor, the profiles are public.
+func convertAuthorForCaller(ctx context.Context, author *domain.Author) *proto.Author {
+	res := convertAuthorToProto(author)
+	if actor, ok := auth.ActorFromContext(ctx); ok && actor.CanModify(author.ID) {
+		res.Email = author.Email
+	}
+
+	return res
+}
+
+// convertAuthorToProto converts the public profile of an author.
+func convertAuthorToProto(author *domain.Author) *proto.Author {
+	return &proto.Author{
+		Id:        int64(author.ID),
+		Name:      author.Name,
+		Bio:       author.Bio,
+		CreatedAt: timestamppb.New(author.CreatedAt),
+		UpdatedAt: timestamppb.New(author.UpdatedAt),
+	}
+}
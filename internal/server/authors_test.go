@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	proto "github.com/kokhno-nikolay/news/api/proto"
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/auth"
+	mock_repository "github.com/kokhno-nikolay/news/internal/repository/mocks"
+	"github.com/kokhno-nikolay/news/internal/service"
+)
+
+func TestAuthorsServer_Email(t *testing.T) {
+	authors := []*domain.Author{
+		{ID: 1, Name: "First", Email: "first@example.com"},
+		{ID: 2, Name: "Second", Email: "second@example.com"},
+	}
+
+	tests := []struct {
+		name   string
+		actor  *auth.Actor
+		emails []string
+	}{
+		{"anonymous", nil, []string{"", ""}},
+		{"reader", &auth.Actor{Role: auth.RoleReader}, []string{"", ""}},
+		{"author", &auth.Actor{Role: auth.RoleAuthor, AuthorID: 2}, []string{"", "second@example.com"}},
+		{"editor", &auth.Actor{Role: auth.RoleEditor}, []string{"first@example.com", "second@example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mock_repository.NewMockAuthors(gomock.NewController(t))
+			s := NewAuthorsServer(*service.NewAuthorService(repo))
+
+			repo.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Return(authors, nil)
+			repo.EXPECT().Get(gomock.Any(), 2).Return(authors[1], nil)
+
+			ctx := context.Background()
+			if tt.actor != nil {
+				ctx = auth.WithActor(ctx, tt.actor)
+			}
+
+			list, err := s.ListAuthors(ctx, &proto.ListAuthorsRequest{})
+			assert.NoError(t, err)
+			if assert.Len(t, list.Authors, 2) {
+				assert.Equal(t, tt.emails[0], list.Authors[0].Email)
+				assert.Equal(t, tt.emails[1], list.Authors[1].Email)
+			}
+
+			author, err := s.GetAuthor(ctx, &proto.GetAuthorRequest{Id: 2})
+			assert.NoError(t, err)
+			assert.Equal(t, "Second", author.Name)
+			assert.Equal(t, tt.emails[1], author.Email)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/status"
+
+	proto "github.com/kokhno-nikolay/news/api/proto"
+	"github.com/kokhno-nikolay/news/domain"
+)
+
+// @Summary		Batch get posts
+// @Description	Getting up to 100 posts by id, missing posts are reported per item
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		ids                  query      []int  true   "Post IDs"
+// @Param		include_unpublished  query      bool   false  "Include drafts the caller can modify"
+// @Success		200      {object}   proto.BatchPostsResponse
+// @Failure		400      {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/posts/batch [get]
+func (s *Server) BatchGet(ctx context.Context, req *proto.BatchGetRequest) (*proto.BatchPostsResponse, error) {
+	res, err := s.postService.BatchGet(ctx, convertIDsFromProto(req.Ids), req.IncludeUnpublished)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertBatchResultsToProto(res), nil
+}
+
+// @Summary		Batch create posts
+// @Description	Creating up to 100 posts in one transaction, partial writes the valid items only
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		input    body       proto.BatchCreateRequest  true  "Posts"
+// @Success		200      {object}   proto.BatchPostsResponse
+// @Failure		400,403  {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/posts/batch [post]
+func (s *Server) BatchCreate(ctx context.Context, req *proto.BatchCreateRequest) (*proto.BatchPostsResponse, error) {
+	inputs := make([]domain.PostInput, len(req.Items))
+	for i, item := range req.Items {
+		inputs[i] = domain.PostInput{
+			Title:       item.Title,
+			Content:     item.Content,
+			AuthorID:    int(item.AuthorId),
+			CategoryIDs: convertIDsFromProto(item.CategoryIds),
+			Tags:        convertTagsFromProto(item.Tags),
+		}
+	}
+
+	res, err := s.postService.BatchCreate(ctx, inputs, req.Partial)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertBatchResultsToProto(res), nil
+}
+
+// @Summary		Batch update posts
+// @Description	Updating up to 100 posts in one transaction, every item carries its version and optional update mask
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		input    body       proto.BatchUpdateRequest  true  "Posts"
+// @Success		200      {object}   proto.BatchPostsResponse
+// @Failure		400,403  {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/posts/batch [patch]
+func (s *Server) BatchUpdate(ctx context.Context, req *proto.BatchUpdateRequest) (*proto.BatchPostsResponse, error) {
+	updates := make([]domain.PostBatchUpdate, len(req.Items))
+	for i, item := range req.Items {
+		updates[i] = domain.PostBatchUpdate{
+			ID: int(item.Id),
+			Input: domain.PostInput{
+				Title:       item.Title,
+				Content:     item.Content,
+				CategoryIDs: convertIDsFromProto(item.CategoryIds),
+				Tags:        convertTagsFromProto(item.Tags),
+				Version:     int(item.Version),
+				Fields:      filterMaskPaths(item.UpdateMask.GetPaths()),
+			},
+		}
+	}
+
+	res, err := s.postService.BatchUpdate(ctx, updates, req.Partial)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertBatchResultsToProto(res), nil
+}
+
+// @Summary		Batch delete posts
+// @Description	Moving up to 100 posts to the trash
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		input    body       proto.BatchDeleteRequest  true  "Post IDs"
+// @Success		200      {object}   proto.BatchPostsResponse
+// @Failure		400,403  {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/posts/batch/delete [post]
+func (s *Server) BatchDelete(ctx context.Context, req *proto.BatchDeleteRequest) (*proto.BatchPostsResponse, error) {
+	res, err := s.postService.BatchDelete(ctx, convertIDsFromProto(req.Ids), req.Partial)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertBatchResultsToProto(res), nil
+}
+
+func convertBatchResultsToProto(results []*domain.PostBatchResult) *proto.BatchPostsResponse {
+	res := make([]*proto.BatchPostResult, len(results))
+	for i, result := range results {
+		res[i] = &proto.BatchPostResult{
+			Index: int32(result.Index),
+		}
+
+		if result.Err != nil {
+			res[i].Error = status.Convert(toStatusError(result.Err)).Proto()
+			continue
+		}
+
+		res[i].Post = convertPostToProto(result.Post)
+	}
+
+	return &proto.BatchPostsResponse{
+		Results: res,
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+// errorResponse is the body the gateway answers failed HTTP requests with,
+// the JSON form of the gRPC status. Only the API docs refer to it.
+type errorResponse struct {
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Details []interface{} `json:"details"`
+}
+
+// errorsInterceptor translates errors returned by the service layer into gRPC
+// statuses, so the gateway can map them onto the right HTTP codes.
+func errorsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return resp, nil
+}
+
+// errorsStreamInterceptor is errorsInterceptor for streaming RPCs.
+func errorsStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		return toStatusError(err)
+	}
+
+	return nil
+}
+
+func toStatusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, pkgerrors.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, pkgerrors.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, pkgerrors.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, pkgerrors.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, pkgerrors.ErrInvalidArgument),
+		errors.Is(err, pkgerrors.ErrInvalidPageToken),
+		errors.Is(err, pkgerrors.ErrInvalidSortField),
+		errors.Is(err, pkgerrors.ErrInvalidSortOrder),
+		errors.Is(err, pkgerrors.ErrInvalidStatus),
+		errors.Is(err, pkgerrors.ErrInvalidUpdateMask):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, pkgerrors.ErrEventsExpired):
+		return status.Error(codes.OutOfRange, err.Error())
+	case errors.Is(err, pkgerrors.ErrInvalidStatusTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, pkgerrors.ErrVersionConflict),
+		errors.Is(err, pkgerrors.ErrBatchAborted):
+		return status.Error(codes.Aborted, err.Error())
+	}
+
+	return err
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/internal/service"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+func TestToStatusError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"invalid argument", fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument), codes.InvalidArgument},
+		{"invalid sort field", fmt.Errorf("%w: %q", pkgerrors.ErrInvalidSortField, "views"), codes.InvalidArgument},
+		{"invalid update mask", pkgerrors.ErrInvalidUpdateMask, codes.InvalidArgument},
+		{"not found", pkgerrors.ErrNotFound, codes.NotFound},
+		{"permission denied", pkgerrors.ErrPermissionDenied, codes.PermissionDenied},
+		{"invalid transition", pkgerrors.ErrInvalidStatusTransition, codes.FailedPrecondition},
+		{"version conflict", pkgerrors.ErrVersionConflict, codes.Aborted},
+		{"events expired", pkgerrors.ErrEventsExpired, codes.OutOfRange},
+		{"status kept", status.Error(codes.Unavailable, "shutting down"), codes.Unavailable},
+		{"unknown", errors.New("connection refused"), codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.code, status.Code(toStatusError(tt.err)))
+		})
+	}
+}
+
+func TestErrorsInterceptor_ValidationError(t *testing.T) {
+	posts := service.NewPostsService(nil, nil, &config.Config{})
+
+	_, err := errorsInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return posts.Get(ctx, -1, false)
+	})
+
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "invalid argument: <id> must be greater than or equal to zero", st.Message())
+}
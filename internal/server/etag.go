@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kokhno-nikolay/news/domain"
+)
+
+const (
+	etagHeader = "etag"
+	// ifMatchHeader is the If-Match HTTP header as forwarded by the gateway.
+	ifMatchHeader = runtime.MetadataPrefix + "if-match"
+)
+
+// setETag sends the post version as ETag header metadata, which the gateway
+// returns as the HTTP ETag.
+func setETag(ctx context.Context, post *domain.Post) {
+	_ = grpc.SetHeader(ctx, metadata.Pairs(etagHeader, strconv.Quote(strconv.Itoa(post.Version))))
+}
+
+// anyVersion is the version expected by If-Match: *, the post only has to
+// exist.
+const anyVersion = -1
+
+// expectedVersion returns the version from the request, falling back to the
+// If-Match header of HTTP requests. Zero means none was given, anyVersion
+// that any version will do.
+func expectedVersion(ctx context.Context, version int64) (int, error) {
+	if version < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "version must not be negative, got %d", version)
+	}
+	if version != 0 {
+		return int(version), nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, nil
+	}
+
+	values := md.Get(ifMatchHeader)
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	if strings.TrimSpace(values[0]) == "*" {
+		return anyVersion, nil
+	}
+
+	tag := strings.TrimPrefix(strings.TrimSpace(values[0]), "W/")
+	v, err := strconv.Atoi(strings.Trim(tag, `"`))
+	if err != nil || v <= 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "If-Match must be a post ETag, got %q", values[0])
+	}
+
+	return v, nil
+}
+
+// outgoingHeaderMatcher exposes the ETag under its HTTP name, drops the
+// request ID accessLog already returns and keeps the gateway default for all
+// other metadata.
+func outgoingHeaderMatcher(key string) (string, bool) {
+	switch key {
+	case etagHeader:
+		return "ETag", true
+	case requestIDHeader:
+		return "", false
+	}
+
+	return runtime.MetadataHeaderPrefix + key, true
+}
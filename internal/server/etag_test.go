@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	proto "github.com/kokhno-nikolay/news/api/proto"
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/auth"
+	mock_repository "github.com/kokhno-nikolay/news/internal/repository/mocks"
+	"github.com/kokhno-nikolay/news/internal/service"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+func withIfMatch(ifMatch string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(ifMatchHeader, ifMatch))
+}
+
+func TestExpectedVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		version int64
+		want    int
+		code    codes.Code
+	}{
+		{name: "none", ctx: context.Background()},
+		{name: "body", ctx: withIfMatch(`"2"`), version: 5, want: 5},
+		{name: "etag", ctx: withIfMatch(`"3"`), want: 3},
+		{name: "weak etag", ctx: withIfMatch(`W/"3"`), want: 3},
+		{name: "any", ctx: withIfMatch(` * `), want: anyVersion},
+		{name: "malformed", ctx: withIfMatch(`"three"`), code: codes.InvalidArgument},
+		{name: "not positive", ctx: withIfMatch(`"0"`), code: codes.InvalidArgument},
+		{name: "negative body", ctx: withIfMatch(`*`), version: anyVersion, code: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := expectedVersion(tt.ctx, tt.version)
+
+			assert.Equal(t, tt.code, status.Code(err))
+			assert.Equal(t, tt.want, version)
+		})
+	}
+}
+
+func newPostServer(t *testing.T) (*Server, *mock_repository.MockPosts) {
+	posts := mock_repository.NewMockPosts(gomock.NewController(t))
+
+	return &Server{postService: *service.NewPostsService(posts, nil, &config.Config{})}, posts
+}
+
+func editor(ctx context.Context) context.Context {
+	return auth.WithActor(ctx, &auth.Actor{Role: auth.RoleEditor})
+}
+
+func TestServer_Update_IfMatch(t *testing.T) {
+	req := &proto.UpdateRequest{Id: 1, Title: "Title", Content: "Content"}
+	current := &domain.Post{ID: 1, Title: "Title", Content: "Content", Version: 3}
+
+	t.Run("matching etag", func(t *testing.T) {
+		s, posts := newPostServer(t)
+
+		posts.EXPECT().Get(gomock.Any(), 1).Return(current, nil)
+		posts.EXPECT().Update(gomock.Any(), 1, gomock.Any()).DoAndReturn(func(_ context.Context, _ int, input *domain.PostInput) (*domain.Post, error) {
+			assert.Equal(t, 3, input.Version)
+			return &domain.Post{ID: 1, Version: 4}, nil
+		})
+
+		res, err := s.Update(editor(withIfMatch(`"3"`)), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), res.Version)
+	})
+
+	t.Run("stale etag", func(t *testing.T) {
+		s, posts := newPostServer(t)
+
+		posts.EXPECT().Get(gomock.Any(), 1).Return(current, nil)
+		posts.EXPECT().Update(gomock.Any(), 1, gomock.Any()).Return(nil, pkgerrors.ErrVersionConflict)
+
+		_, err := s.Update(editor(withIfMatch(`"2"`)), req)
+
+		// the gateway answers Aborted with 409
+		assert.Equal(t, codes.Aborted, status.Code(toStatusError(err)))
+	})
+
+	t.Run("malformed etag", func(t *testing.T) {
+		s, _ := newPostServer(t)
+
+		_, err := s.Update(editor(withIfMatch(`v3`)), req)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("any version", func(t *testing.T) {
+		s, posts := newPostServer(t)
+
+		posts.EXPECT().Get(gomock.Any(), 1).Return(current, nil).Times(2)
+		posts.EXPECT().Update(gomock.Any(), 1, gomock.Any()).DoAndReturn(func(_ context.Context, _ int, input *domain.PostInput) (*domain.Post, error) {
+			assert.Equal(t, 3, input.Version)
+			return &domain.Post{ID: 1, Version: 4}, nil
+		})
+
+		res, err := s.Update(editor(withIfMatch(`*`)), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), res.Version)
+	})
+
+	t.Run("any version of a missing post", func(t *testing.T) {
+		s, posts := newPostServer(t)
+
+		posts.EXPECT().Get(gomock.Any(), 1).Return(nil, pkgerrors.ErrNotFound)
+
+		_, err := s.Update(editor(withIfMatch(`*`)), req)
+
+		assert.Equal(t, codes.NotFound, status.Code(toStatusError(err)))
+	})
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	desc "github.com/kokhno-nikolay/news/api/proto"
+)
+
+// updateMaskMetadata carries the update mask the gateway derived from the
+// body of a PATCH /posts request.
+const updateMaskMetadata = "x-update-mask"
+
+// maxPatchBodySize bounds the PATCH body read to derive the mask. It is the
+// most the gateway can pass on, the gRPC server takes messages of up to
+// 4 MiB.
+const maxPatchBodySize = 4 << 20
+
+// patchMaskAnnotator derives the update mask of PATCH /posts from the fields
+// present in the JSON body. The gateway only does that by itself when the
+// body maps onto a single field, while UpdateRequest takes the whole body.
+func patchMaskAnnotator(_ context.Context, r *http.Request) metadata.MD {
+	if r.Method != http.MethodPatch || r.URL.Path != "/posts" || r.Body == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxPatchBodySize))
+	if err != nil {
+		// the gateway fails to decode the body with the same error
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{err}))
+		return nil
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	// a malformed body is reported by the gateway when it decodes the request
+	mask, err := runtime.FieldMaskFromRequestBody(bytes.NewReader(body), &desc.UpdateRequest{})
+	if err != nil {
+		return nil
+	}
+
+	paths := filterMaskPaths(mask.Paths)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	return metadata.Pairs(updateMaskMetadata, strings.Join(paths, ","))
+}
+
+// errReader fails every read with err.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// updateMaskPaths returns the paths of the request mask, falling back to the
+// mask derived by the gateway.
+func updateMaskPaths(ctx context.Context, mask *fieldmaskpb.FieldMask) []string {
+	paths := mask.GetPaths()
+	if len(paths) == 0 {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(updateMaskMetadata); len(values) > 0 {
+				paths = strings.Split(values[0], ",")
+			}
+		}
+	}
+
+	return filterMaskPaths(paths)
+}
+
+// filterMaskPaths drops the paths that only identify the post, and the empty
+// path the gateway derives from an empty body.
+func filterMaskPaths(paths []string) []string {
+	res := make([]string, 0, len(paths))
+	for _, path := range paths {
+		switch path {
+		case "", "id", "version", "update_mask":
+		default:
+			res = append(res, path)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestPatchMaskAnnotator(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		mask   []string
+	}{
+		{"fields present", http.MethodPatch, "/posts", `{"id": 1, "version": 2, "title": "Title", "tags": []}`, []string{"title", "tags"}},
+		{"empty body", http.MethodPatch, "/posts", `{}`, nil},
+		{"malformed body", http.MethodPatch, "/posts", `{"title": `, nil},
+		{"put", http.MethodPut, "/posts", `{"title": "Title"}`, nil},
+		{"other path", http.MethodPatch, "/tags", `{"title": "Title"}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+
+			md := patchMaskAnnotator(context.Background(), r)
+
+			if tt.mask == nil {
+				assert.Empty(t, md.Get(updateMaskMetadata))
+			} else {
+				values := md.Get(updateMaskMetadata)
+				if assert.Len(t, values, 1) {
+					assert.ElementsMatch(t, tt.mask, strings.Split(values[0], ","))
+				}
+			}
+
+			// the gateway still reads the whole body
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.body, string(body))
+		})
+	}
+}
+
+func TestPatchMaskAnnotator_TooLarge(t *testing.T) {
+	body := `{"title": "` + strings.Repeat("a", maxPatchBodySize) + `"}`
+	r := httptest.NewRequest(http.MethodPatch, "/posts", strings.NewReader(body))
+
+	md := patchMaskAnnotator(context.Background(), r)
+	assert.Nil(t, md)
+
+	// the gateway gets the error instead of a truncated body
+	_, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	assert.True(t, errors.As(err, &tooLarge))
+}
+
+func TestUpdateMaskPaths(t *testing.T) {
+	derived := metadata.NewIncomingContext(context.Background(), metadata.Pairs(updateMaskMetadata, "id,version,content,tags"))
+
+	// the derived mask is used without one in the request
+	assert.Equal(t, []string{"content", "tags"}, updateMaskPaths(derived, nil))
+
+	// the request mask wins
+	assert.Equal(t, []string{"title"}, updateMaskPaths(derived, &fieldmaskpb.FieldMask{Paths: []string{"title"}}))
+
+	assert.Empty(t, updateMaskPaths(context.Background(), nil))
+}
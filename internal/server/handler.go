@@ -2,9 +2,12 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	proto "github.com/kokhno-nikolay/news/api/proto"
 	"github.com/kokhno-nikolay/news/domain"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -13,46 +16,113 @@ import (
 // @Tags		posts
 // @Accept		json
 // @Produce		json
-// @Param		id           path       int true  "Post ID"
+// @Param		id                  path       int  true   "Post ID"
+// @Param		include_unpublished query      bool false  "Return the post before it is published"
 // @Success		200          {object}   domain.Post
 // @Failure		400,404      {object}   errorResponse
 // @Failure		500          {object}   errorResponse
 // @Failure		default      {object}   errorResponse
 // @Router		/posts/{id}  [get]
 func (s *Server) Get(ctx context.Context, req *proto.GetRequest) (*proto.GetResponse, error) {
-	post, err := s.postService.Get(ctx, int(req.Id))
+	post, err := s.postService.Get(ctx, int(req.Id), req.IncludeUnpublished)
 	if err != nil {
 		return nil, err
 	}
 
+	setETag(ctx, post)
+
 	return &proto.GetResponse{
 		Post: convertPostToProto(post),
 	}, nil
 }
 
 // @Summary		Get post list
-// @Description	Getting post list
+// @Description	Getting post list page by page, newest posts first
 // @Tags		posts
 // @Accept		json
 // @Produce		json
-// @Success		200      {object}   domain.Post
+// @Param		limit                 query      int     false  "Page size"
+// @Param		offset                query      int     false  "Offset, ignored when page_token is set"
+// @Param		page_token            query      string  false  "Cursor from a previous response"
+// @Param		include_total_count   query      bool    false  "Count all posts matching the filter"
+// @Param		filter.created_from   query      string  false  "Created at or after, RFC 3339"
+// @Param		filter.created_to     query      string  false  "Created before, RFC 3339"
+// @Param		filter.updated_from   query      string  false  "Updated at or after, RFC 3339"
+// @Param		filter.updated_to     query      string  false  "Updated before, RFC 3339"
+// @Param		filter.title_contains query      string  false  "Title substring, case insensitive"
+// @Param		filter.tag            query      string  false  "Tag slug"
+// @Param		filter.category_id    query      int     false  "Category ID"
+// @Param		filter.author_id      query      int     false  "Author ID"
+// @Param		filter.statuses       query      []string false "Post statuses, published by default"
+// @Param		sort_by               query      string  false  "created_at, updated_at or title"
+// @Param		sort_order            query      string  false  "asc or desc"
+// @Success		200      {object}   domain.PostList
 // @Failure		400,404  {object}   errorResponse
 // @Failure		500      {object}   errorResponse
 // @Failure		default  {object}   errorResponse
-// @Router		/posts [get]
+// @Router		/posts/list [get]
 func (s *Server) List(ctx context.Context, req *proto.ListRequest) (*proto.ListResponse, error) {
-	res, err := s.postService.List(ctx, int(req.Limit))
+	res, err := s.postService.List(ctx, domain.PostListInput{
+		Filter:       convertFilterFromProto(req.Filter),
+		SortBy:       req.SortBy,
+		SortOrder:    req.SortOrder,
+		Limit:        int(req.Limit),
+		Offset:       int(req.Offset),
+		PageToken:    req.PageToken,
+		IncludeTotal: req.IncludeTotalCount,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	posts := make([]*proto.Post, 0)
-	for _, item := range res {
+	for _, item := range res.Posts {
 		posts = append(posts, convertPostToProto(item))
 	}
 
 	return &proto.ListResponse{
-		Posts: posts,
+		Posts:         posts,
+		NextPageToken: res.NextPageToken,
+		PrevPageToken: res.PrevPageToken,
+		TotalCount:    int64(res.TotalCount),
+	}, nil
+}
+
+// @Summary		Search posts
+// @Description	Full-text search over post titles and content, most relevant first
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		q        query      string  true   "Search query"
+// @Param		limit    query      int     false  "Page size"
+// @Param		offset   query      int     false  "Offset"
+// @Success		200      {array}    domain.SearchResult
+// @Failure		400,404  {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/posts/search [get]
+func (s *Server) Search(ctx context.Context, req *proto.SearchRequest) (*proto.SearchResponse, error) {
+	res, err := s.postService.Search(ctx, domain.SearchInput{
+		Query:  req.Q,
+		Limit:  int(req.Limit),
+		Offset: int(req.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*proto.SearchResult, 0)
+	for _, item := range res {
+		results = append(results, &proto.SearchResult{
+			Post:           convertPostToProto(item.Post),
+			Rank:           float32(item.Rank),
+			TitleHighlight: item.TitleHighlight,
+			Snippet:        item.Snippet,
+		})
+	}
+
+	return &proto.SearchResponse{
+		Results: results,
 	}, nil
 }
 
@@ -69,42 +139,69 @@ func (s *Server) List(ctx context.Context, req *proto.ListRequest) (*proto.ListR
 // @Router		/posts [post]
 func (s *Server) Create(ctx context.Context, req *proto.CreateRequest) (*proto.Post, error) {
 	res, err := s.postService.Create(ctx, domain.PostInput{
-		Title:   req.Title,
-		Content: req.Content,
+		Title:       req.Title,
+		Content:     req.Content,
+		AuthorID:    int(req.AuthorId),
+		CategoryIDs: convertIDsFromProto(req.CategoryIds),
+		Tags:        convertTagsFromProto(req.Tags),
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	setETag(ctx, res)
+
 	return convertPostToProto(res), nil
 }
 
 // @Summary		Update post
-// @Description	Updatting post entity
+// @Description	Updatting post entity, PATCH only updates the fields present in the body
 // @Tags		posts
 // @Accept		json
 // @Produce		json
-// @Param		id      path        int                 true    "Post ID"
-// @Param		input   body        domain.PostInput    true    "Post content"
+// @Param		id       path        int                 true    "Post ID"
+// @Param		If-Match header      string              false   "Post ETag, instead of version in the body, * updates any version"
+// @Param		input    body        domain.PostInput    true    "Post content"
 // @Success		201     {object}    domain.Post
+// @Failure		409     {object}    errorResponse
 // @Failure		400,404 {object}    errorResponse
 // @Failure		500     {object}    errorResponse
 // @Failure		default {object}    errorResponse
 // @Router		/posts/{id} [put]
 func (s *Server) Update(ctx context.Context, req *proto.UpdateRequest) (*proto.Post, error) {
+	version, err := expectedVersion(ctx, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	// the update still fails should the post change before it is applied
+	if version == anyVersion {
+		post, err := s.postService.Get(ctx, int(req.Id), true)
+		if err != nil {
+			return nil, err
+		}
+		version = post.Version
+	}
+
 	res, err := s.postService.Update(ctx, int(req.Id), domain.PostInput{
-		Title:   req.Title,
-		Content: req.Content,
+		Title:       req.Title,
+		Content:     req.Content,
+		CategoryIDs: convertIDsFromProto(req.CategoryIds),
+		Tags:        convertTagsFromProto(req.Tags),
+		Version:     version,
+		Fields:      updateMaskPaths(ctx, req.UpdateMask),
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	setETag(ctx, res)
+
 	return convertPostToProto(res), nil
 }
 
 // @Summary     Delete post
-// @Description Deletting post entity
+// @Description Moves post entity to the trash
 // @Tags		posts
 // @Accept		json
 // @Produce		json
@@ -125,12 +222,271 @@ func (s *Server) Delete(ctx context.Context, req *proto.DeleteRequest) (*proto.D
 	}, nil
 }
 
+// @Summary     Submit post
+// @Description Sends a draft to review
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		id      body        int true   "Post ID"
+// @Success		200     {object}    domain.Post
+// @Failure		400,404 {object}    errorResponse
+// @Failure		500     {object}    errorResponse
+// @Failure		default {object}    errorResponse
+// @Router		/posts/submit  [post]
+func (s *Server) Submit(ctx context.Context, req *proto.SubmitRequest) (*proto.Post, error) {
+	res, err := s.postService.Submit(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPostToProto(res), nil
+}
+
+// @Summary     Publish post
+// @Description Makes a draft or a reviewed post public
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		id      body        int true   "Post ID"
+// @Success		200     {object}    domain.Post
+// @Failure		400,404 {object}    errorResponse
+// @Failure		500     {object}    errorResponse
+// @Failure		default {object}    errorResponse
+// @Router		/posts/publish  [post]
+func (s *Server) Publish(ctx context.Context, req *proto.PublishRequest) (*proto.Post, error) {
+	res, err := s.postService.Publish(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPostToProto(res), nil
+}
+
+// @Summary     Unpublish post
+// @Description Turns a published or archived post back into a draft
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		id      body        int true   "Post ID"
+// @Success		200     {object}    domain.Post
+// @Failure		400,404 {object}    errorResponse
+// @Failure		500     {object}    errorResponse
+// @Failure		default {object}    errorResponse
+// @Router		/posts/unpublish  [post]
+func (s *Server) Unpublish(ctx context.Context, req *proto.UnpublishRequest) (*proto.Post, error) {
+	res, err := s.postService.Unpublish(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPostToProto(res), nil
+}
+
+// @Summary     Archive post
+// @Description Archives a post in any other status
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		id      body        int true   "Post ID"
+// @Success		200     {object}    domain.Post
+// @Failure		400,404 {object}    errorResponse
+// @Failure		500     {object}    errorResponse
+// @Failure		default {object}    errorResponse
+// @Router		/posts/archive  [post]
+func (s *Server) Archive(ctx context.Context, req *proto.ArchiveRequest) (*proto.Post, error) {
+	res, err := s.postService.Archive(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPostToProto(res), nil
+}
+
+// @Summary     Schedule post
+// @Description Publishes a draft or a reviewed post at publish_at
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		input   body        proto.ScheduleRequest true "Post ID and publication time"
+// @Success		200     {object}    domain.Post
+// @Failure		400,404 {object}    errorResponse
+// @Failure		500     {object}    errorResponse
+// @Failure		default {object}    errorResponse
+// @Router		/posts/schedule  [post]
+func (s *Server) Schedule(ctx context.Context, req *proto.ScheduleRequest) (*proto.Post, error) {
+	publishAt, err := requireTimestamp(req.PublishAt, "publish_at")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.postService.Schedule(ctx, int(req.Id), publishAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPostToProto(res), nil
+}
+
+// @Summary     Reschedule post
+// @Description Moves the publication of a scheduled post
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		input   body        proto.RescheduleRequest true "Post ID and publication time"
+// @Success		200     {object}    domain.Post
+// @Failure		400,404 {object}    errorResponse
+// @Failure		500     {object}    errorResponse
+// @Failure		default {object}    errorResponse
+// @Router		/posts/schedule  [patch]
+func (s *Server) Reschedule(ctx context.Context, req *proto.RescheduleRequest) (*proto.Post, error) {
+	publishAt, err := requireTimestamp(req.PublishAt, "publish_at")
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.postService.Reschedule(ctx, int(req.Id), publishAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPostToProto(res), nil
+}
+
+// @Summary     Cancel post schedule
+// @Description Keeps a scheduled post from being published
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		id      query       int true   "Post ID"
+// @Success		200     {object}    domain.Post
+// @Failure		400,404 {object}    errorResponse
+// @Failure		500     {object}    errorResponse
+// @Failure		default {object}    errorResponse
+// @Router		/posts/schedule  [delete]
+func (s *Server) CancelSchedule(ctx context.Context, req *proto.CancelScheduleRequest) (*proto.Post, error) {
+	res, err := s.postService.CancelSchedule(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPostToProto(res), nil
+}
+
 func convertPostToProto(post *domain.Post) *proto.Post {
+	categories := make([]*proto.Category, 0, len(post.Categories))
+	for _, item := range post.Categories {
+		categories = append(categories, convertCategoryToProto(item))
+	}
+
+	tags := make([]*proto.Tag, 0, len(post.Tags))
+	for _, item := range post.Tags {
+		tags = append(tags, convertTagToProto(item))
+	}
+
+	var author *proto.Author
+	if post.Author != nil {
+		author = convertAuthorToProto(post.Author)
+	}
+
+	var publishedAt, publishAt, deletedAt *timestamppb.Timestamp
+	if post.PublishedAt != nil {
+		publishedAt = timestamppb.New(*post.PublishedAt)
+	}
+	if post.PublishAt != nil {
+		publishAt = timestamppb.New(*post.PublishAt)
+	}
+	if post.DeletedAt != nil {
+		deletedAt = timestamppb.New(*post.DeletedAt)
+	}
+
 	return &proto.Post{
-		Id:        int64(post.ID),
-		Title:     post.Title,
-		Content:   post.Content,
-		CreatedAt: timestamppb.New(post.CreatedAt),
-		UpdatedAt: timestamppb.New(post.UpdatedAt),
+		Id:          int64(post.ID),
+		Title:       post.Title,
+		Content:     post.Content,
+		Author:      author,
+		Categories:  categories,
+		Tags:        tags,
+		Status:      string(post.Status),
+		PublishedAt: publishedAt,
+		PublishAt:   publishAt,
+		CreatedAt:   timestamppb.New(post.CreatedAt),
+		UpdatedAt:   timestamppb.New(post.UpdatedAt),
+		DeletedAt:   deletedAt,
+		Version:     int64(post.Version),
+	}
+}
+
+func convertIDsFromProto(ids []int64) []int {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	res := make([]int, len(ids))
+	for i, id := range ids {
+		res[i] = int(id)
+	}
+
+	return res
+}
+
+// convertTagsFromProto keeps "no tags given" as nil, proto3 can not tell
+// an empty repeated field from a missing one.
+func convertTagsFromProto(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return tags
+}
+
+func convertFilterFromProto(filter *proto.PostFilter) domain.PostFilter {
+	if filter == nil {
+		return domain.PostFilter{}
 	}
+
+	return domain.PostFilter{
+		CreatedFrom:   convertTimestampFromProto(filter.CreatedFrom),
+		CreatedTo:     convertTimestampFromProto(filter.CreatedTo),
+		UpdatedFrom:   convertTimestampFromProto(filter.UpdatedFrom),
+		UpdatedTo:     convertTimestampFromProto(filter.UpdatedTo),
+		TitleContains: filter.TitleContains,
+		Tag:           filter.Tag,
+		CategoryID:    int(filter.CategoryId),
+		AuthorID:      int(filter.AuthorId),
+		Statuses:      convertStatusesFromProto(filter.Statuses),
+	}
+}
+
+func convertStatusesFromProto(statuses []string) []domain.PostStatus {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	res := make([]domain.PostStatus, len(statuses))
+	for i, status := range statuses {
+		res[i] = domain.PostStatus(status)
+	}
+
+	return res
+}
+
+func requireTimestamp(ts *timestamppb.Timestamp, name string) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, fmt.Errorf("%w: <%s> is required", pkgerrors.ErrInvalidArgument, name)
+	}
+
+	if err := ts.CheckValid(); err != nil {
+		return time.Time{}, fmt.Errorf("%w: <%s> is invalid: %v", pkgerrors.ErrInvalidArgument, name, err)
+	}
+
+	return ts.AsTime(), nil
+}
+
+func convertTimestampFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+
+	t := ts.AsTime()
+	return &t
 }
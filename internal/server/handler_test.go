@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	proto "github.com/kokhno-nikolay/news/api/proto"
+)
+
+func TestServer_Schedule_PublishAt(t *testing.T) {
+	tests := []struct {
+		name      string
+		publishAt *timestamppb.Timestamp
+	}{
+		{name: "missing"},
+		{name: "invalid", publishAt: &timestamppb.Timestamp{Seconds: 1, Nanos: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newPostServer(t)
+
+			_, err := s.Schedule(editor(context.Background()), &proto.ScheduleRequest{Id: 1, PublishAt: tt.publishAt})
+			assert.Equal(t, codes.InvalidArgument, status.Code(toStatusError(err)))
+
+			_, err = s.Reschedule(editor(context.Background()), &proto.RescheduleRequest{Id: 1, PublishAt: tt.publishAt})
+			assert.Equal(t, codes.InvalidArgument, status.Code(toStatusError(err)))
+		})
+	}
+}
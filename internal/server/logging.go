@@ -0,0 +1,188 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/kokhno-nikolay/news/internal/logging"
+)
+
+const (
+	requestIDHeader = "x-request-id"
+
+	// healthMethodPrefix covers the health checks of the orchestrator, which
+	// are too frequent to be worth logging
+	healthMethodPrefix = "/grpc.health.v1.Health/"
+
+	maxRequestIDLength = 128
+)
+
+// loggingInterceptor tags the context of an RPC with a logger carrying its
+// request ID, taken from the x-request-id metadata sent by the gateway or
+// any other caller, or assigned here. The ID is sent back in the response
+// header and the RPC is logged once it completes.
+func loggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	ctx, logger, requestID := rpcLogger(ctx, info.FullMethod)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+
+	resp, err := handler(ctx, req)
+	if !strings.HasPrefix(info.FullMethod, healthMethodPrefix) {
+		logRPC(logger, err, time.Since(start))
+	}
+
+	return resp, err
+}
+
+// loggingStreamInterceptor is loggingInterceptor for streaming RPCs, they
+// are logged when the stream ends.
+func loggingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	ctx, logger, requestID := rpcLogger(ss.Context(), info.FullMethod)
+	_ = ss.SetHeader(metadata.Pairs(requestIDHeader, requestID))
+
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	if !strings.HasPrefix(info.FullMethod, healthMethodPrefix) {
+		logRPC(logger, err, time.Since(start))
+	}
+
+	return err
+}
+
+func rpcLogger(ctx context.Context, fullMethod string) (context.Context, *logrus.Entry, string) {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDHeader); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	requestID = ensureRequestID(requestID)
+
+	logger := logging.FromContext(ctx).WithFields(logrus.Fields{
+		"request_id": requestID,
+		"method":     fullMethod,
+	})
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		logger = logger.WithField("trace_id", span.TraceID().String())
+	}
+
+	return logging.NewContext(ctx, logger), logger, requestID
+}
+
+// logRPC logs a completed RPC, at error level when it failed on the server
+// side.
+func logRPC(logger *logrus.Entry, err error, elapsed time.Duration) {
+	code := status.Code(err)
+	logger = logger.WithFields(logrus.Fields{
+		"code":     code.String(),
+		"duration": elapsed.Seconds(),
+	})
+
+	switch code {
+	case codes.OK:
+		logger.Info("rpc completed")
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss:
+		logger.WithError(err).Error("rpc failed")
+	default:
+		logger.WithError(err).Info("rpc failed")
+	}
+}
+
+// accessLog assigns every HTTP request a request ID, passed on to the gRPC
+// server by the gateway and returned in the X-Request-Id header, and logs
+// the request once it is served.
+func accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := ensureRequestID(r.Header.Get(requestIDHeader))
+		r.Header.Set(requestIDHeader, requestID)
+		w.Header().Set(requestIDHeader, requestID)
+
+		logger := logging.FromContext(r.Context()).WithField("request_id", requestID)
+		if span := trace.SpanContextFromContext(r.Context()); span.IsValid() {
+			logger = logger.WithField("trace_id", span.TraceID().String())
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(logging.NewContext(r.Context(), logger)))
+
+		logger.WithFields(logrus.Fields{
+			"method":   r.Method,
+			"path":     r.URL.Path,
+			"status":   rec.status,
+			"duration": time.Since(start).Seconds(),
+		}).Info("http request")
+	})
+}
+
+// incomingHeaderMatcher passes the request ID on to the gRPC server as
+// x-request-id metadata, other headers follow the gateway default.
+func incomingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, requestIDHeader) {
+		return requestIDHeader, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// ensureRequestID keeps a request ID set by the caller unless it is unfit
+// for logs, and assigns a random one otherwise.
+func ensureRequestID(requestID string) string {
+	if requestID != "" && len(requestID) <= maxRequestIDLength && isPrintable(requestID) {
+		return requestID
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+
+	return hex.EncodeToString(b)
+}
+
+func isPrintable(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x21 || s[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// statusRecorder remembers the status code written by a handler. It passes
+// Flush on, so server-sent events keep streaming.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/kokhno-nikolay/news/internal/metrics"
+)
+
+// metricsInterceptor counts and times RPCs. It comes first in the chain, so
+// it sees the status codes errorsInterceptor maps errors onto and the calls
+// rejected by authentication.
+func metricsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+	metrics.ObserveRPC(info.FullMethod, "unary", status.Code(err), time.Since(start))
+
+	return resp, err
+}
+
+// metricsStreamInterceptor is metricsInterceptor for streaming RPCs, they
+// are timed until the stream ends.
+func metricsStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	err := handler(srv, ss)
+	metrics.ObserveRPC(info.FullMethod, streamType(info), status.Code(err), time.Since(start))
+
+	return err
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return "bidi_stream"
+	case info.IsClientStream:
+		return "client_stream"
+	}
+
+	return "server_stream"
+}
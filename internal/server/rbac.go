@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kokhno-nikolay/news/internal/auth"
+)
+
+// rbacInterceptor checks the caller's role against the policy. It runs after
+// authInterceptor, so a call without an actor is an anonymous call to a public
+// method and is let through.
+func rbacInterceptor(policy *auth.Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := checkRole(ctx, policy, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// rbacStreamInterceptor is rbacInterceptor for streaming RPCs.
+func rbacStreamInterceptor(policy *auth.Policy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkRole(ss.Context(), policy, info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func checkRole(ctx context.Context, policy *auth.Policy, method string) error {
+	actor, ok := auth.ActorFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if !policy.Allowed(method, actor.Role) {
+		return status.Error(codes.PermissionDenied, permissionDeniedMessage(policy, method, actor.Role))
+	}
+
+	return nil
+}
+
+func permissionDeniedMessage(policy *auth.Policy, method string, role auth.Role) string {
+	roles := policy.Roles(method)
+	if len(roles) == 0 {
+		return fmt.Sprintf("%s is not allowed for any role", method)
+	}
+
+	names := make([]string, len(roles))
+	for i, r := range roles {
+		names[i] = string(r)
+	}
+
+	return fmt.Sprintf("role %q is not allowed to call %s, requires one of: %s", role, method, strings.Join(names, ", "))
+}
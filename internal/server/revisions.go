@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	proto "github.com/kokhno-nikolay/news/api/proto"
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/pkg/diff"
+)
+
+// @Summary		List post revisions
+// @Description	Getting the change history of a post, newest revision first
+// @Tags		revisions
+// @Accept		json
+// @Produce		json
+// @Param		post_id  query      int  true   "Post ID"
+// @Param		limit    query      int  false  "Page size"
+// @Param		offset   query      int  false  "Offset"
+// @Success		200      {array}    domain.PostRevision
+// @Failure		400,404  {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/posts/revisions/list [get]
+func (s *Server) ListRevisions(ctx context.Context, req *proto.ListRevisionsRequest) (*proto.ListRevisionsResponse, error) {
+	res, err := s.postService.ListRevisions(ctx, int(req.PostId), int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*proto.PostRevision, 0, len(res))
+	for _, item := range res {
+		revisions = append(revisions, convertRevisionToProto(item))
+	}
+
+	return &proto.ListRevisionsResponse{
+		Revisions: revisions,
+	}, nil
+}
+
+// @Summary		Get post revision
+// @Description	Getting a single revision of a post
+// @Tags		revisions
+// @Accept		json
+// @Produce		json
+// @Param		post_id   query      int  true  "Post ID"
+// @Param		revision  query      int  true  "Revision number"
+// @Success		200       {object}   domain.PostRevision
+// @Failure		400,404   {object}   errorResponse
+// @Failure		500       {object}   errorResponse
+// @Failure		default   {object}   errorResponse
+// @Router		/posts/revisions [get]
+func (s *Server) GetRevision(ctx context.Context, req *proto.GetRevisionRequest) (*proto.PostRevision, error) {
+	res, err := s.postService.GetRevision(ctx, int(req.PostId), int(req.Revision))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertRevisionToProto(res), nil
+}
+
+// @Summary		Diff post revisions
+// @Description	Line-level diff of the title and content of two revisions
+// @Tags		revisions
+// @Accept		json
+// @Produce		json
+// @Param		post_id        query      int  true  "Post ID"
+// @Param		from_revision  query      int  true  "Older revision"
+// @Param		to_revision    query      int  true  "Newer revision"
+// @Success		200            {object}   domain.RevisionDiff
+// @Failure		400,404        {object}   errorResponse
+// @Failure		500            {object}   errorResponse
+// @Failure		default        {object}   errorResponse
+// @Router		/posts/revisions/diff [get]
+func (s *Server) DiffRevisions(ctx context.Context, req *proto.DiffRevisionsRequest) (*proto.DiffRevisionsResponse, error) {
+	res, err := s.postService.DiffRevisions(ctx, int(req.PostId), int(req.FromRevision), int(req.ToRevision))
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.DiffRevisionsResponse{
+		PostId:       int64(res.PostID),
+		FromRevision: int64(res.From),
+		ToRevision:   int64(res.To),
+		Title:        convertDiffToProto(res.Title),
+		Content:      convertDiffToProto(res.Content),
+	}, nil
+}
+
+// @Summary		Restore post revision
+// @Description	Brings back the title and content of a revision as a new revision
+// @Tags		revisions
+// @Accept		json
+// @Produce		json
+// @Param		input    body       proto.RestoreRevisionRequest  true  "Post ID and revision"
+// @Success		200      {object}   domain.Post
+// @Failure		400,404  {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/posts/revisions/restore [post]
+func (s *Server) RestoreRevision(ctx context.Context, req *proto.RestoreRevisionRequest) (*proto.Post, error) {
+	res, err := s.postService.RestoreRevision(ctx, int(req.PostId), int(req.Revision))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPostToProto(res), nil
+}
+
+func convertRevisionToProto(revision *domain.PostRevision) *proto.PostRevision {
+	return &proto.PostRevision{
+		Id:        int64(revision.ID),
+		PostId:    int64(revision.PostID),
+		Revision:  int64(revision.Revision),
+		Title:     revision.Title,
+		Content:   revision.Content,
+		EditedBy:  int64(revision.EditedBy),
+		CreatedAt: timestamppb.New(revision.CreatedAt),
+	}
+}
+
+func convertDiffToProto(lines []diff.Line) []*proto.DiffLine {
+	res := make([]*proto.DiffLine, 0, len(lines))
+	for _, line := range lines {
+		res = append(res, &proto.DiffLine{
+			Op:   string(line.Op),
+			Text: line.Text,
+		})
+	}
+
+	return res
+}
@@ -2,55 +2,184 @@ package server
 
 import (
 	"context"
-	"log"
+	"errors"
 	"net"
 	"net/http"
+	"sync"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	desc "github.com/kokhno-nikolay/news/api/proto"
 	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/internal/auth"
+	"github.com/kokhno-nikolay/news/internal/health"
+	"github.com/kokhno-nikolay/news/internal/metrics"
 	"github.com/kokhno-nikolay/news/internal/service"
 )
 
 type Server struct {
 	desc.UnimplementedPostsServer
-	postService service.PostService
+	postService    service.PostService
+	taxonomyServer *TaxonomyServer
+	authorsServer  *AuthorsServer
+	webhooksServer *WebhooksServer
+	healthServer   *grpchealth.Server
+	checker        *health.Checker
+
+	mu         sync.Mutex
+	grpcServer *grpc.Server
+	httpServer *http.Server
+	// stopping is closed once the servers start shutting down, it ends the
+	// Watch streams that would otherwise keep them up until the deadline
+	stopping     chan struct{}
+	stoppingOnce sync.Once
 }
 
-func NewServer(postService service.PostService) *Server {
+func NewServer(services *service.Service, checker *health.Checker) *Server {
 	return &Server{
-		postService: postService,
+		postService:    services.PostService,
+		taxonomyServer: NewTaxonomyServer(services.TaxonomyService),
+		authorsServer:  NewAuthorsServer(services.AuthorService),
+		webhooksServer: NewWebhooksServer(services.WebhookService),
+		healthServer:   grpchealth.NewServer(),
+		checker:        checker,
+		stopping:       make(chan struct{}),
 	}
 }
 
+// Drain reports the service as NOT_SERVING over gRPC health checks and
+// unready on /readyz, for good. Requests are still served, so callers have
+// time to notice before the servers stop.
+func (s *Server) Drain() {
+	s.healthServer.Shutdown()
+	s.checker.Drain()
+}
+
 func (s *Server) StartGrpcServer(cfg *config.Config) error {
+	verifier, err := auth.NewVerifier(cfg)
+	if err != nil {
+		return err
+	}
+
+	policy, err := auth.LoadPolicy(cfg.RBACPolicyPath)
+	if err != nil {
+		return err
+	}
+
 	grpcServer := grpc.NewServer(
 		grpc.Creds(insecure.NewCredentials()),
+		// spans of RPCs continue the trace context sent by the gateway
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			metricsInterceptor,
+			loggingInterceptor,
+			errorsInterceptor,
+			authInterceptor(verifier, cfg.PublicMethods),
+			rbacInterceptor(policy),
+		),
+		grpc.ChainStreamInterceptor(
+			metricsStreamInterceptor,
+			loggingStreamInterceptor,
+			errorsStreamInterceptor,
+			authStreamInterceptor(verifier, cfg.PublicMethods),
+			rbacStreamInterceptor(policy),
+		),
 	)
 
 	reflection.Register(grpcServer)
 
-	desc.RegisterPostsServer(grpcServer, &Server{})
+	desc.RegisterPostsServer(grpcServer, s)
+	desc.RegisterTaxonomyServer(grpcServer, s.taxonomyServer)
+	desc.RegisterAuthorsServer(grpcServer, s.authorsServer)
+	desc.RegisterWebhooksServer(grpcServer, s.webhooksServer)
+
+	// the overall status is reported under the empty service name
+	healthpb.RegisterHealthServer(grpcServer, s.healthServer)
+	for _, name := range []string{
+		"",
+		desc.Posts_ServiceDesc.ServiceName,
+		desc.Taxonomy_ServiceDesc.ServiceName,
+		desc.Authors_ServiceDesc.ServiceName,
+		desc.Webhooks_ServiceDesc.ServiceName,
+	} {
+		s.healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
 
 	list, err := net.Listen("tcp", cfg.GrpcAddress)
 	if err != nil {
 		return err
 	}
 
-	log.Printf("gRPC server listening at %v\n", cfg.GrpcAddress)
+	s.mu.Lock()
+	if s.isStopping() {
+		s.mu.Unlock()
+		list.Close()
+		return nil
+	}
+	s.grpcServer = grpcServer
+	s.mu.Unlock()
+
+	logrus.WithField("address", cfg.GrpcAddress).Info("gRPC server listening")
 
+	// Serve returns nil once GracefulStop or Stop is called
 	return grpcServer.Serve(list)
 }
 
+// ShutdownGrpcServer ends Watch streams and waits for the other RPCs in
+// flight to complete. The RPCs still running once ctx is done are cancelled.
+func (s *Server) ShutdownGrpcServer(ctx context.Context) error {
+	s.stopStreams()
+
+	s.mu.Lock()
+	grpcServer := s.grpcServer
+	s.mu.Unlock()
+
+	if grpcServer == nil {
+		return nil
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		grpcServer.Stop()
+		return ctx.Err()
+	}
+}
+
 func (s *Server) StartHttpServer(ctx context.Context, cfg *config.Config) error {
-	mux := runtime.NewServeMux()
+	// the connections of the gateway to the gRPC server are closed along
+	// with ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the gateway passes the Authorization header on as authorization metadata,
+	// X-Request-Id as x-request-id and If-Match as grpcgateway-if-match
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+		runtime.WithMetadata(patchMaskAnnotator),
+	)
 
+	// the client spans of the gateway hop pass the trace context on to the
+	// gRPC server
 	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 	}
 
 	err := desc.RegisterPostsHandlerFromEndpoint(ctx, mux, cfg.GrpcAddress, opts)
@@ -58,7 +187,104 @@ func (s *Server) StartHttpServer(ctx context.Context, cfg *config.Config) error
 		return err
 	}
 
-	log.Printf("http server listening at %v\n", cfg.HttpAddress)
+	err = desc.RegisterTaxonomyHandlerFromEndpoint(ctx, mux, cfg.GrpcAddress, opts)
+	if err != nil {
+		return err
+	}
+
+	err = desc.RegisterAuthorsHandlerFromEndpoint(ctx, mux, cfg.GrpcAddress, opts)
+	if err != nil {
+		return err
+	}
 
-	return http.ListenAndServe(cfg.HttpAddress, mux)
+	err = desc.RegisterWebhooksHandlerFromEndpoint(ctx, mux, cfg.GrpcAddress, opts)
+	if err != nil {
+		return err
+	}
+
+	// server-sent events are served next to the gateway, through a client of
+	// the gRPC server like the gateway itself
+	conn, err := grpc.DialContext(ctx, cfg.GrpcAddress, opts...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	httpMux := http.NewServeMux()
+	httpMux.Handle("/", mux)
+	httpMux.Handle("/posts/events", eventsHandler(desc.NewPostsClient(conn)))
+
+	// every request starts a span, or continues the trace of the caller, and
+	// is logged along with its request ID
+	handler := otelhttp.NewHandler(accessLog(httpMux), "http",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+	)
+
+	// scrapes and probes are neither traced nor logged
+	rootMux := http.NewServeMux()
+	rootMux.Handle("/", handler)
+	rootMux.Handle("/metrics", metrics.Handler())
+	rootMux.Handle("/healthz", health.LiveHandler())
+	rootMux.Handle("/readyz", health.ReadyHandler(s.checker))
+
+	httpServer := &http.Server{
+		Addr:    cfg.HttpAddress,
+		Handler: rootMux,
+	}
+
+	s.mu.Lock()
+	if s.isStopping() {
+		s.mu.Unlock()
+		return nil
+	}
+	s.httpServer = httpServer
+	s.mu.Unlock()
+
+	logrus.WithField("address", cfg.HttpAddress).Info("http server listening")
+
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// ShutdownHttpServer ends server-sent event streams and waits for the other
+// requests in flight to complete, at most until ctx is done. It goes first,
+// the gateway needs the gRPC server to complete its requests.
+func (s *Server) ShutdownHttpServer(ctx context.Context) error {
+	s.stopStreams()
+
+	s.mu.Lock()
+	httpServer := s.httpServer
+	s.mu.Unlock()
+
+	if httpServer == nil {
+		return nil
+	}
+
+	if err := httpServer.Shutdown(ctx); err != nil {
+		httpServer.Close()
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) stopStreams() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stoppingOnce.Do(func() { close(s.stopping) })
+}
+
+func (s *Server) isStopping() bool {
+	select {
+	case <-s.stopping:
+		return true
+	default:
+		return false
+	}
 }
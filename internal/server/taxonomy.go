@@ -0,0 +1,268 @@
+package server
+
+import (
+	"context"
+
+	proto "github.com/kokhno-nikolay/news/api/proto"
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type TaxonomyServer struct {
+	proto.UnimplementedTaxonomyServer
+	taxonomyService service.TaxonomyService
+}
+
+func NewTaxonomyServer(taxonomyService service.TaxonomyService) *TaxonomyServer {
+	return &TaxonomyServer{
+		taxonomyService: taxonomyService,
+	}
+}
+
+// @Summary		Get category
+// @Description	Getting category entity by id.
+// @Tags		taxonomy
+// @Accept		json
+// @Produce		json
+// @Param		id           query      int true  "Category ID"
+// @Success		200          {object}   domain.Category
+// @Failure		400,404      {object}   errorResponse
+// @Failure		500          {object}   errorResponse
+// @Failure		default      {object}   errorResponse
+// @Router		/categories  [get]
+func (s *TaxonomyServer) GetCategory(ctx context.Context, req *proto.GetCategoryRequest) (*proto.Category, error) {
+	category, err := s.taxonomyService.GetCategory(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertCategoryToProto(category), nil
+}
+
+// @Summary		Get category list
+// @Description	Getting all categories ordered by name
+// @Tags		taxonomy
+// @Accept		json
+// @Produce		json
+// @Success		200      {array}    domain.Category
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/categories/list [get]
+func (s *TaxonomyServer) ListCategories(ctx context.Context, req *proto.ListCategoriesRequest) (*proto.ListCategoriesResponse, error) {
+	res, err := s.taxonomyService.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make([]*proto.Category, 0)
+	for _, item := range res {
+		categories = append(categories, convertCategoryToProto(item))
+	}
+
+	return &proto.ListCategoriesResponse{
+		Categories: categories,
+	}, nil
+}
+
+// @Summary		Create category
+// @Description	Creates a new category, the slug is derived from the name when empty
+// @Tags		taxonomy
+// @Accept		json
+// @Produce		json
+// @Param		input   body        domain.CategoryInput  true  "Category"
+// @Success		201     {object}    domain.Category
+// @Failure		400,409 {object}    errorResponse
+// @Failure		500     {object}    errorResponse
+// @Failure		default {object}    errorResponse
+// @Router		/categories [post]
+func (s *TaxonomyServer) CreateCategory(ctx context.Context, req *proto.CreateCategoryRequest) (*proto.Category, error) {
+	res, err := s.taxonomyService.CreateCategory(ctx, domain.CategoryInput{
+		Name: req.Name,
+		Slug: req.Slug,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertCategoryToProto(res), nil
+}
+
+// @Summary		Update category
+// @Description	Updating category entity
+// @Tags		taxonomy
+// @Accept		json
+// @Produce		json
+// @Param		input   body        domain.CategoryInput  true  "Category"
+// @Success		200     {object}    domain.Category
+// @Failure		400,404,409 {object} errorResponse
+// @Failure		500     {object}    errorResponse
+// @Failure		default {object}    errorResponse
+// @Router		/categories [patch]
+func (s *TaxonomyServer) UpdateCategory(ctx context.Context, req *proto.UpdateCategoryRequest) (*proto.Category, error) {
+	res, err := s.taxonomyService.UpdateCategory(ctx, int(req.Id), domain.CategoryInput{
+		Name: req.Name,
+		Slug: req.Slug,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertCategoryToProto(res), nil
+}
+
+// @Summary		Delete category
+// @Description	Deleting category entity, posts lose the category but stay intact
+// @Tags		taxonomy
+// @Accept		json
+// @Produce		json
+// @Param		id      query       int true   "Category ID"
+// @Success		200     {bool}      true
+// @Failure		400,404 {object}    errorResponse
+// @Failure		500     {object}    errorResponse
+// @Failure		default {object}    errorResponse
+// @Router		/categories [delete]
+func (s *TaxonomyServer) DeleteCategory(ctx context.Context, req *proto.DeleteCategoryRequest) (*proto.DeleteResponse, error) {
+	success, err := s.taxonomyService.DeleteCategory(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.DeleteResponse{
+		Success: success,
+	}, nil
+}
+
+// @Summary		Get tag
+// @Description	Getting tag entity by id.
+// @Tags		taxonomy
+// @Accept		json
+// @Produce		json
+// @Param		id      query      int true  "Tag ID"
+// @Success		200     {object}   domain.Tag
+// @Failure		400,404 {object}   errorResponse
+// @Failure		500     {object}   errorResponse
+// @Failure		default {object}   errorResponse
+// @Router		/tags   [get]
+func (s *TaxonomyServer) GetTag(ctx context.Context, req *proto.GetTagRequest) (*proto.Tag, error) {
+	tag, err := s.taxonomyService.GetTag(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertTagToProto(tag), nil
+}
+
+// @Summary		Get tag list
+// @Description	Getting tags ordered by name
+// @Tags		taxonomy
+// @Accept		json
+// @Produce		json
+// @Param		limit    query      int     false  "Page size"
+// @Param		offset   query      int     false  "Offset"
+// @Success		200      {array}    domain.Tag
+// @Failure		400      {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/tags/list [get]
+func (s *TaxonomyServer) ListTags(ctx context.Context, req *proto.ListTagsRequest) (*proto.ListTagsResponse, error) {
+	res, err := s.taxonomyService.ListTags(ctx, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]*proto.Tag, 0)
+	for _, item := range res {
+		tags = append(tags, convertTagToProto(item))
+	}
+
+	return &proto.ListTagsResponse{
+		Tags: tags,
+	}, nil
+}
+
+// @Summary		Create tag
+// @Description	Creates a new tag, the slug is derived from the name when empty
+// @Tags		taxonomy
+// @Accept		json
+// @Produce		json
+// @Param		input   body        domain.TagInput  true  "Tag"
+// @Success		201     {object}    domain.Tag
+// @Failure		400,409 {object}    errorResponse
+// @Failure		500     {object}    errorResponse
+// @Failure		default {object}    errorResponse
+// @Router		/tags [post]
+func (s *TaxonomyServer) CreateTag(ctx context.Context, req *proto.CreateTagRequest) (*proto.Tag, error) {
+	res, err := s.taxonomyService.CreateTag(ctx, domain.TagInput{
+		Name: req.Name,
+		Slug: req.Slug,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertTagToProto(res), nil
+}
+
+// @Summary		Update tag
+// @Description	Updating tag entity
+// @Tags		taxonomy
+// @Accept		json
+// @Produce		json
+// @Param		input   body        domain.TagInput  true  "Tag"
+// @Success		200     {object}    domain.Tag
+// @Failure		400,404,409 {object} errorResponse
+// @Failure		500     {object}    errorResponse
+// @Failure		default {object}    errorResponse
+// @Router		/tags [patch]
+func (s *TaxonomyServer) UpdateTag(ctx context.Context, req *proto.UpdateTagRequest) (*proto.Tag, error) {
+	res, err := s.taxonomyService.UpdateTag(ctx, int(req.Id), domain.TagInput{
+		Name: req.Name,
+		Slug: req.Slug,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertTagToProto(res), nil
+}
+
+// @Summary		Delete tag
+// @Description	Deleting tag entity, posts lose the tag but stay intact
+// @Tags		taxonomy
+// @Accept		json
+// @Produce		json
+// @Param		id      query       int true   "Tag ID"
+// @Success		200     {bool}      true
+// @Failure		400,404 {object}    errorResponse
+// @Failure		500     {object}    errorResponse
+// @Failure		default {object}    errorResponse
+// @Router		/tags [delete]
+func (s *TaxonomyServer) DeleteTag(ctx context.Context, req *proto.DeleteTagRequest) (*proto.DeleteResponse, error) {
+	success, err := s.taxonomyService.DeleteTag(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.DeleteResponse{
+		Success: success,
+	}, nil
+}
+
+func convertCategoryToProto(category *domain.Category) *proto.Category {
+	return &proto.Category{
+		Id:        int64(category.ID),
+		Name:      category.Name,
+		Slug:      category.Slug,
+		CreatedAt: timestamppb.New(category.CreatedAt),
+	}
+}
+
+func convertTagToProto(tag *domain.Tag) *proto.Tag {
+	return &proto.Tag{
+		Id:        int64(tag.ID),
+		Name:      tag.Name,
+		Slug:      tag.Slug,
+		CreatedAt: timestamppb.New(tag.CreatedAt),
+	}
+}
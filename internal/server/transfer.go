@@ -0,0 +1,152 @@
+package server
+
+import (
+	"io"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/status"
+
+	proto "github.com/kokhno-nikolay/news/api/proto"
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/logging"
+)
+
+const (
+	// importChunkSize is the number of records written at a time.
+	importChunkSize = domain.MaxBatchSize
+
+	// maxImportErrors caps the failures listed in an import response.
+	maxImportErrors = 1000
+)
+
+// @Summary		Import posts
+// @Description	Creating posts from newline delimited ImportRequest records, failed records are listed by position. Posts get new ids, created_at and published_at are the time of the import
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		input    body       proto.ImportRequest  true  "Records, one JSON object per line"
+// @Success		200      {object}   proto.ImportResponse
+// @Failure		400,403  {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/posts/import [post]
+func (s *Server) Import(stream proto.Posts_ImportServer) error {
+	ctx := stream.Context()
+	res := &proto.ImportResponse{}
+	chunk := make([]domain.PostImport, 0, importChunkSize)
+
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		results, err := s.postService.Import(ctx, chunk)
+		if err != nil {
+			return err
+		}
+
+		offset := res.Received - int64(len(chunk))
+		for _, result := range results {
+			if result.Err == nil {
+				res.Imported++
+				continue
+			}
+
+			res.Failed++
+			if len(res.Errors) < maxImportErrors {
+				res.Errors = append(res.Errors, &proto.ImportError{
+					Index: offset + int64(result.Index),
+					Error: status.Convert(toStatusError(result.Err)).Proto(),
+				})
+			}
+		}
+		chunk = chunk[:0]
+
+		logging.FromContext(stream.Context()).WithFields(logrus.Fields{
+			"received": res.Received,
+			"imported": res.Imported,
+			"failed":   res.Failed,
+		}).Info("import: chunk written")
+
+		return nil
+	}
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			if err := flush(); err != nil {
+				return err
+			}
+
+			return stream.SendAndClose(res)
+		}
+		if err != nil {
+			return err
+		}
+
+		res.Received++
+		chunk = append(chunk, convertImportFromProto(req.Post))
+
+		if len(chunk) == importChunkSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// @Summary		Export posts
+// @Description	Streaming the posts matching the filter as newline delimited ExportResponse messages, oldest first
+// @Tags		posts
+// @Accept		json
+// @Produce		json
+// @Param		filter.statuses       query      []string  false  "Statuses"
+// @Param		filter.author_id      query      int       false  "Author ID"
+// @Success		200      {object}   proto.ExportResponse
+// @Failure		400,403  {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/posts/export [get]
+func (s *Server) Export(req *proto.ExportRequest, stream proto.Posts_ExportServer) error {
+	exported := 0
+
+	return s.postService.Export(stream.Context(), convertFilterFromProto(req.Filter), func(posts []*domain.Post, total int) error {
+		for _, post := range posts {
+			err := stream.Send(&proto.ExportResponse{
+				Item: &proto.ExportResponse_Post{Post: convertPostToProto(post)},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		exported += len(posts)
+
+		return stream.Send(&proto.ExportResponse{
+			Item: &proto.ExportResponse_Progress{Progress: &proto.ExportProgress{
+				Exported: int64(exported),
+				Total:    int64(total),
+			}},
+		})
+	})
+}
+
+func convertImportFromProto(post *proto.Post) domain.PostImport {
+	input := domain.PostInput{
+		Title:    post.GetTitle(),
+		Content:  post.GetContent(),
+		AuthorID: int(post.GetAuthor().GetId()),
+	}
+
+	for _, category := range post.GetCategories() {
+		input.CategoryIDs = append(input.CategoryIDs, int(category.GetId()))
+	}
+
+	for _, tag := range post.GetTags() {
+		input.Tags = append(input.Tags, tag.GetName())
+	}
+
+	return domain.PostImport{
+		Input:  input,
+		Status: domain.PostStatus(post.GetStatus()),
+	}
+}
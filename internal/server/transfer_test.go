@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	proto "github.com/kokhno-nikolay/news/api/proto"
+	"github.com/kokhno-nikolay/news/domain"
+)
+
+// importStream sends the records to Import and keeps its response.
+type importStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	records []*proto.ImportRequest
+	res     *proto.ImportResponse
+}
+
+func (s *importStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *importStream) Recv() (*proto.ImportRequest, error) {
+	if len(s.records) == 0 {
+		return nil, io.EOF
+	}
+
+	record := s.records[0]
+	s.records = s.records[1:]
+
+	return record, nil
+}
+
+func (s *importStream) SendAndClose(res *proto.ImportResponse) error {
+	s.res = res
+	return nil
+}
+
+func TestServer_Import_Chunks(t *testing.T) {
+	s, posts := newPostServer(t)
+
+	// records 5 and 130 have an unknown status, the others are written a
+	// chunk at a time
+	stream := &importStream{ctx: editor(context.Background())}
+	for i := 0; i < 150; i++ {
+		status := "draft"
+		if i == 5 || i == 130 {
+			status = "deleted"
+		}
+
+		stream.records = append(stream.records, &proto.ImportRequest{Post: &proto.Post{
+			Title:   "Title",
+			Content: "Content",
+			Author:  &proto.Author{Id: 1},
+			Status:  status,
+		}})
+	}
+
+	id := 0
+	createBatch := func(_ context.Context, inputs []*domain.PostInput) ([]*domain.Post, error) {
+		created := make([]*domain.Post, len(inputs))
+		for i := range created {
+			id++
+			created[i] = &domain.Post{ID: id, Status: domain.PostStatusDraft}
+		}
+		return created, nil
+	}
+	gomock.InOrder(
+		posts.EXPECT().CreateBatch(gomock.Any(), gomock.Len(importChunkSize-1)).DoAndReturn(createBatch),
+		posts.EXPECT().CreateBatch(gomock.Any(), gomock.Len(49)).DoAndReturn(createBatch),
+	)
+
+	err := s.Import(stream)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150), stream.res.Received)
+	assert.Equal(t, int64(148), stream.res.Imported)
+	assert.Equal(t, int64(2), stream.res.Failed)
+
+	// failures are indexed across chunks
+	if assert.Len(t, stream.res.Errors, 2) {
+		assert.Equal(t, int64(5), stream.res.Errors[0].Index)
+		assert.Equal(t, int64(130), stream.res.Errors[1].Index)
+		assert.Equal(t, int32(codes.InvalidArgument), stream.res.Errors[1].Error.Code)
+	}
+}
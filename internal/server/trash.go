@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+
+	proto "github.com/kokhno-nikolay/news/api/proto"
+)
+
+// @Summary		List trash
+// @Description	Getting deleted posts, most recently deleted first
+// @Tags		trash
+// @Accept		json
+// @Produce		json
+// @Param		limit    query      int  false  "Page size"
+// @Param		offset   query      int  false  "Offset"
+// @Success		200      {array}    domain.Post
+// @Failure		400,404  {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/posts/trash [get]
+func (s *Server) ListTrash(ctx context.Context, req *proto.ListTrashRequest) (*proto.ListTrashResponse, error) {
+	res, err := s.postService.ListTrash(ctx, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([]*proto.Post, 0, len(res))
+	for _, item := range res {
+		posts = append(posts, convertPostToProto(item))
+	}
+
+	return &proto.ListTrashResponse{
+		Posts: posts,
+	}, nil
+}
+
+// @Summary		Restore post
+// @Description	Takes a post out of the trash
+// @Tags		trash
+// @Accept		json
+// @Produce		json
+// @Param		input    body       proto.RestoreRequest  true  "Post ID"
+// @Success		200      {object}   domain.Post
+// @Failure		400,404  {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/posts/restore [post]
+func (s *Server) Restore(ctx context.Context, req *proto.RestoreRequest) (*proto.Post, error) {
+	res, err := s.postService.Restore(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPostToProto(res), nil
+}
+
+// @Summary		Purge post
+// @Description	Permanently removes a post from the trash
+// @Tags		trash
+// @Accept		json
+// @Produce		json
+// @Param		id       query      int  true  "Post ID"
+// @Success		200      {bool}     true
+// @Failure		400,404  {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/posts/trash [delete]
+func (s *Server) Purge(ctx context.Context, req *proto.PurgeRequest) (*proto.DeleteResponse, error) {
+	success, err := s.postService.Purge(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.DeleteResponse{
+		Success: success,
+	}, nil
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	proto "github.com/kokhno-nikolay/news/api/proto"
+	"github.com/kokhno-nikolay/news/domain"
+)
+
+// sseHeartbeat is how often an idle event stream gets a comment, so proxies
+// do not close it.
+const sseHeartbeat = 15 * time.Second
+
+// @Summary		Watch posts
+// @Description	Streaming post events as newline delimited JSON, see /posts/events for server-sent events
+// @Tags		posts
+// @Produce		json
+// @Param		after_id             query      int   false  "Resume after this event"
+// @Param		include_unpublished  query      bool  false  "Include events of posts the caller can modify"
+// @Success		200      {object}   proto.PostEvent
+// @Failure		400      {object}   errorResponse
+// @Failure		500      {object}   errorResponse
+// @Failure		default  {object}   errorResponse
+// @Router		/posts/watch [get]
+func (s *Server) Watch(req *proto.WatchRequest, stream proto.Posts_WatchServer) error {
+	// headers go out at once, so a client can tell an accepted stream from a
+	// rejected call before the first event arrives
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	// the stream ends when the server shuts down, clients resume from the
+	// last event they got on another replica
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-s.stopping:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := s.postService.Watch(ctx, req.AfterId, req.IncludeUnpublished, func(event *domain.PostEvent) error {
+		return stream.Send(convertPostEventToProto(event))
+	})
+	if err != nil && s.isStopping() {
+		return status.Error(codes.Unavailable, "the server is shutting down")
+	}
+
+	return err
+}
+
+// @Summary		Post events
+// @Description	Streaming post events as server-sent events, browsers resume with Last-Event-ID on reconnect
+// @Tags		posts
+// @Produce		text/event-stream
+// @Param		after_id             query      int   false  "Resume after this event"
+// @Param		include_unpublished  query      bool  false  "Include events of posts the caller can modify"
+// @Success		200      {object}   proto.PostEvent
+// @Failure		400      {string}   string
+// @Failure		500      {string}   string
+// @Router		/posts/events [get]
+//
+// eventsHandler serves Watch as server-sent events. It calls the gRPC server
+// the way the gateway does, so authentication and the policy apply alike.
+func eventsHandler(client proto.PostsClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		req := &proto.WatchRequest{
+			IncludeUnpublished: r.URL.Query().Get("include_unpublished") == "true",
+		}
+
+		lastID := r.Header.Get("Last-Event-ID")
+		if lastID == "" {
+			lastID = r.URL.Query().Get("after_id")
+		}
+		if lastID != "" {
+			id, err := strconv.ParseInt(lastID, 10, 64)
+			if err != nil {
+				http.Error(w, "invalid event id", http.StatusBadRequest)
+				return
+			}
+			req.AfterId = id
+		}
+
+		ctx := metadata.AppendToOutgoingContext(r.Context(), requestIDHeader, r.Header.Get(requestIDHeader))
+		if authorization := r.Header.Get("Authorization"); authorization != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, authorizationHeader, authorization)
+		}
+
+		stream, err := client.Watch(ctx, req)
+		if err != nil {
+			writeStatusError(w, err)
+			return
+		}
+
+		// a rejected call ends without headers, its status comes with Recv
+		if md, err := stream.Header(); err != nil || md == nil {
+			_, err := stream.Recv()
+			writeStatusError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		events := make(chan *proto.PostEvent)
+		errs := make(chan error, 1)
+		go func() {
+			for {
+				event, err := stream.Recv()
+				if err != nil {
+					errs <- err
+					return
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		heartbeat := time.NewTicker(sseHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-events:
+				data, err := protojson.Marshal(event)
+				if err != nil {
+					return
+				}
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data)
+			case err := <-errs:
+				if st := status.Convert(err); ctx.Err() == nil && st.Code() != codes.Canceled {
+					fmt.Fprintf(w, "event: error\ndata: %s\n\n", st.Message())
+					flusher.Flush()
+				}
+				return
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeStatusError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	http.Error(w, st.Message(), runtime.HTTPStatusFromCode(st.Code()))
+}
+
+func convertPostEventToProto(event *domain.PostEvent) *proto.PostEvent {
+	res := &proto.PostEvent{
+		Id:        event.ID,
+		Type:      string(event.Type),
+		PostId:    int64(event.PostID),
+		CreatedAt: timestamppb.New(event.CreatedAt),
+	}
+
+	if event.Post != nil {
+		res.Post = convertPostToProto(event.Post)
+	}
+
+	return res
+}
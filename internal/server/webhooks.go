@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	proto "github.com/kokhno-nikolay/news/api/proto"
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/service"
+)
+
+type WebhooksServer struct {
+	proto.UnimplementedWebhooksServer
+	webhookService service.WebhookService
+}
+
+func NewWebhooksServer(webhookService service.WebhookService) *WebhooksServer {
+	return &WebhooksServer{
+		webhookService: webhookService,
+	}
+}
+
+// @Summary		Create webhook
+// @Description	Subscribes a URL to post events, admins only. Deliveries are signed with HMAC-SHA256 of the returned secret in the X-Webhook-Signature header, the secret can not be read afterwards.
+// @Tags		webhooks
+// @Accept		json
+// @Produce		json
+// @Param		input       body        domain.WebhookInput  true  "Webhook"
+// @Success		200         {object}    domain.WebhookSubscription
+// @Failure		400,401,403 {object}    errorResponse
+// @Failure		500         {object}    errorResponse
+// @Failure		default     {object}    errorResponse
+// @Router		/webhooks [post]
+func (s *WebhooksServer) CreateWebhook(ctx context.Context, req *proto.CreateWebhookRequest) (*proto.WebhookSubscription, error) {
+	events := make([]domain.WebhookEvent, len(req.Events))
+	for i, event := range req.Events {
+		events[i] = domain.WebhookEvent(event)
+	}
+
+	res, err := s.webhookService.Create(ctx, domain.WebhookInput{
+		URL:    req.Url,
+		Events: events,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertWebhookToProto(res), nil
+}
+
+// @Summary		Get webhook list
+// @Description	Getting webhook subscriptions without their secrets, editors only
+// @Tags		webhooks
+// @Accept		json
+// @Produce		json
+// @Param		limit       query      int     false  "Page size"
+// @Param		offset      query      int     false  "Offset"
+// @Success		200         {array}    domain.WebhookSubscription
+// @Failure		400,401,403 {object}   errorResponse
+// @Failure		500         {object}   errorResponse
+// @Failure		default     {object}   errorResponse
+// @Router		/webhooks/list [get]
+func (s *WebhooksServer) ListWebhooks(ctx context.Context, req *proto.ListWebhooksRequest) (*proto.ListWebhooksResponse, error) {
+	res, err := s.webhookService.List(ctx, int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]*proto.WebhookSubscription, 0)
+	for _, item := range res {
+		webhooks = append(webhooks, convertWebhookToProto(item))
+	}
+
+	return &proto.ListWebhooksResponse{
+		Webhooks: webhooks,
+	}, nil
+}
+
+// @Summary		Delete webhook
+// @Description	Deleting webhook subscription along with its delivery log, admins only
+// @Tags		webhooks
+// @Accept		json
+// @Produce		json
+// @Param		id          query       int true   "Webhook ID"
+// @Success		200         {bool}      true
+// @Failure		400,401,403 {object}    errorResponse
+// @Failure		500         {object}    errorResponse
+// @Failure		default     {object}    errorResponse
+// @Router		/webhooks [delete]
+func (s *WebhooksServer) DeleteWebhook(ctx context.Context, req *proto.DeleteWebhookRequest) (*proto.DeleteResponse, error) {
+	success, err := s.webhookService.Delete(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.DeleteResponse{
+		Success: success,
+	}, nil
+}
+
+// @Summary		Test webhook
+// @Description	Sends a webhook.test delivery right away and returns its outcome, editors only. Test deliveries are not retried.
+// @Tags		webhooks
+// @Accept		json
+// @Produce		json
+// @Param		input           body        proto.TestWebhookRequest  true  "Webhook ID"
+// @Success		200             {object}    domain.WebhookDelivery
+// @Failure		400,401,403,404 {object}    errorResponse
+// @Failure		500             {object}    errorResponse
+// @Failure		default         {object}    errorResponse
+// @Router		/webhooks/test [post]
+func (s *WebhooksServer) TestWebhook(ctx context.Context, req *proto.TestWebhookRequest) (*proto.WebhookDelivery, error) {
+	res, err := s.webhookService.Test(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertWebhookDeliveryToProto(res), nil
+}
+
+// @Summary		Get webhook deliveries
+// @Description	Getting the delivery log of a webhook subscription, latest first, editors only
+// @Tags		webhooks
+// @Accept		json
+// @Produce		json
+// @Param		id              query      int     true   "Webhook ID"
+// @Param		limit           query      int     false  "Page size"
+// @Param		offset          query      int     false  "Offset"
+// @Success		200             {array}    domain.WebhookDelivery
+// @Failure		400,401,403,404 {object}   errorResponse
+// @Failure		500             {object}   errorResponse
+// @Failure		default         {object}   errorResponse
+// @Router		/webhooks/deliveries [get]
+func (s *WebhooksServer) ListWebhookDeliveries(ctx context.Context, req *proto.ListWebhookDeliveriesRequest) (*proto.ListWebhookDeliveriesResponse, error) {
+	res, err := s.webhookService.ListDeliveries(ctx, int(req.Id), int(req.Limit), int(req.Offset))
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*proto.WebhookDelivery, 0)
+	for _, item := range res {
+		deliveries = append(deliveries, convertWebhookDeliveryToProto(item))
+	}
+
+	return &proto.ListWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+	}, nil
+}
+
+func convertWebhookToProto(subscription *domain.WebhookSubscription) *proto.WebhookSubscription {
+	events := make([]string, len(subscription.Events))
+	for i, event := range subscription.Events {
+		events[i] = string(event)
+	}
+
+	return &proto.WebhookSubscription{
+		Id:        int64(subscription.ID),
+		Url:       subscription.URL,
+		Secret:    subscription.Secret,
+		Events:    events,
+		CreatedBy: int64(subscription.CreatedBy),
+		CreatedAt: timestamppb.New(subscription.CreatedAt),
+	}
+}
+
+func convertWebhookDeliveryToProto(delivery *domain.WebhookDelivery) *proto.WebhookDelivery {
+	var deliveredAt *timestamppb.Timestamp
+	if delivery.DeliveredAt != nil {
+		deliveredAt = timestamppb.New(*delivery.DeliveredAt)
+	}
+
+	return &proto.WebhookDelivery{
+		Id:             delivery.ID,
+		SubscriptionId: int64(delivery.SubscriptionID),
+		EventId:        delivery.EventID,
+		Event:          string(delivery.Event),
+		Payload:        string(delivery.Payload),
+		Status:         string(delivery.Status),
+		Attempts:       int64(delivery.Attempts),
+		ResponseStatus: int64(delivery.ResponseStatus),
+		LastError:      delivery.LastError,
+		CreatedAt:      timestamppb.New(delivery.CreatedAt),
+		DeliveredAt:    deliveredAt,
+	}
+}
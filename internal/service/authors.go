@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/auth"
+	"github.com/kokhno-nikolay/news/internal/repository"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+const (
+	maxAuthorNameLength = 100
+	maxAuthorBioLength  = 1000
+)
+
+type AuthorService struct {
+	repo repository.Authors
+}
+
+func NewAuthorService(repo repository.Authors) *AuthorService {
+	return &AuthorService{
+		repo: repo,
+	}
+}
+
+func (s *AuthorService) Get(ctx context.Context, id int) (*domain.Author, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	return s.repo.Get(ctx, id)
+}
+
+func (s *AuthorService) List(ctx context.Context, limit, offset int) ([]*domain.Author, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: <limit> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: <offset> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	return s.repo.List(ctx, limit, offset)
+}
+
+// Create registers a new author. Which roles may do so is decided by the
+// RBAC policy.
+func (s *AuthorService) Create(ctx context.Context, input domain.AuthorInput) (*domain.Author, error) {
+	if err := validateAuthorInput(&input); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(ctx, &input)
+}
+
+// Update changes an author profile, which authors can do for themselves.
+func (s *AuthorService) Update(ctx context.Context, id int, input domain.AuthorInput) (*domain.Author, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	actor, ok := auth.ActorFromContext(ctx)
+	if !ok {
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+
+	if !actor.CanModify(id) {
+		return nil, fmt.Errorf("%w: only the author or an editor can change the profile", pkgerrors.ErrPermissionDenied)
+	}
+
+	if err := validateAuthorInput(&input); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Update(ctx, id, &input)
+}
+
+// Delete removes an author, their posts are kept without an author.
+func (s *AuthorService) Delete(ctx context.Context, id int) (bool, error) {
+	return s.repo.Delete(ctx, id)
+}
+
+func validateAuthorInput(input *domain.AuthorInput) error {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Email = strings.TrimSpace(input.Email)
+
+	if len(input.Name) < 2 {
+		return fmt.Errorf("%w: name must be at least 2 characters long", pkgerrors.ErrInvalidArgument)
+	}
+
+	if len(input.Name) > maxAuthorNameLength {
+		return fmt.Errorf("%w: name must be at most %d characters long", pkgerrors.ErrInvalidArgument, maxAuthorNameLength)
+	}
+
+	addr, err := mail.ParseAddress(input.Email)
+	if err != nil || addr.Address != input.Email {
+		return fmt.Errorf("%w: email must be a valid address", pkgerrors.ErrInvalidArgument)
+	}
+
+	if len(input.Bio) > maxAuthorBioLength {
+		return fmt.Errorf("%w: bio must be at most %d characters long", pkgerrors.ErrInvalidArgument, maxAuthorBioLength)
+	}
+
+	return nil
+}
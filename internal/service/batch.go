@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/auth"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+// BatchGet returns the posts in the order of ids. Posts Get would not return
+// are reported as ErrNotFound items.
+func (s *PostService) BatchGet(ctx context.Context, ids []int, includeUnpublished bool) ([]*domain.PostBatchResult, error) {
+	if err := validateBatchSize(len(ids)); err != nil {
+		return nil, err
+	}
+
+	byID, err := s.getBatch(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := newBatchResults(len(ids))
+	for i, id := range ids {
+		post, ok := byID[id]
+		if !ok {
+			results[i].Err = pkgerrors.ErrNotFound
+			continue
+		}
+
+		if err := checkVisible(ctx, post, includeUnpublished); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		results[i].Post = post
+	}
+
+	return results, nil
+}
+
+// BatchCreate creates the posts in one transaction. Unless partial is set a
+// failing item aborts the whole batch and the other items are reported as
+// ErrBatchAborted.
+func (s *PostService) BatchCreate(ctx context.Context, inputs []domain.PostInput, partial bool) ([]*domain.PostBatchResult, error) {
+	if err := validateBatchSize(len(inputs)); err != nil {
+		return nil, err
+	}
+
+	actor, ok := auth.ActorFromContext(ctx)
+	if !ok {
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+
+	results := newBatchResults(len(inputs))
+
+	var valid []int
+	var batch []*domain.PostInput
+	for i := range inputs {
+		if err := prepareCreate(actor, &inputs[i]); err != nil {
+			results[i].Err = err
+			continue
+		}
+		inputs[i].SearchLanguage = s.searchLanguage
+
+		valid = append(valid, i)
+		batch = append(batch, &inputs[i])
+	}
+
+	if !partial && abortBatch(results) {
+		return results, nil
+	}
+
+	posts, err := s.repo.CreateBatch(ctx, batch)
+	if err != nil {
+		var itemErr *domain.BatchItemError
+
+		switch {
+		case !partial && errors.As(err, &itemErr):
+			results[valid[itemErr.Index]].Err = itemErr.Err
+			abortBatch(results)
+			return results, nil
+		case partial && errors.Is(err, pkgerrors.ErrNotFound):
+			// an item links a missing author or category, creating the posts
+			// one by one tells which
+			for n, i := range valid {
+				results[i].Post, results[i].Err = s.repo.Create(ctx, batch[n])
+			}
+			return results, nil
+		default:
+			return nil, err
+		}
+	}
+
+	for n, i := range valid {
+		results[i].Post = posts[n]
+	}
+
+	return results, nil
+}
+
+// BatchUpdate applies the updates in one transaction, with the same all or
+// nothing rule as BatchCreate. Partial batches update the posts one by one.
+func (s *PostService) BatchUpdate(ctx context.Context, updates []domain.PostBatchUpdate, partial bool) ([]*domain.PostBatchResult, error) {
+	if err := validateBatchSize(len(updates)); err != nil {
+		return nil, err
+	}
+
+	results := newBatchResults(len(updates))
+
+	var valid []int
+	var batch []*domain.PostBatchUpdate
+	for i := range updates {
+		if err := s.prepareUpdate(ctx, updates[i].ID, &updates[i].Input); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		valid = append(valid, i)
+		batch = append(batch, &updates[i])
+	}
+
+	if partial {
+		for n, i := range valid {
+			results[i].Post, results[i].Err = s.repo.Update(ctx, batch[n].ID, &batch[n].Input)
+		}
+		return results, nil
+	}
+
+	if abortBatch(results) {
+		return results, nil
+	}
+
+	posts, err := s.repo.UpdateBatch(ctx, batch)
+	if err != nil {
+		var itemErr *domain.BatchItemError
+		if !errors.As(err, &itemErr) {
+			return nil, err
+		}
+
+		results[valid[itemErr.Index]].Err = itemErr.Err
+		abortBatch(results)
+		return results, nil
+	}
+
+	for n, i := range valid {
+		results[i].Post = posts[n]
+	}
+
+	return results, nil
+}
+
+// BatchDelete moves the posts to the trash, with the same all or nothing
+// rule as BatchCreate. The results only carry the ids of deleted posts.
+func (s *PostService) BatchDelete(ctx context.Context, ids []int, partial bool) ([]*domain.PostBatchResult, error) {
+	if err := validateBatchSize(len(ids)); err != nil {
+		return nil, err
+	}
+
+	actor, ok := auth.ActorFromContext(ctx)
+	if !ok {
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+
+	byID, err := s.getBatch(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := newBatchResults(len(ids))
+
+	var valid []int
+	var batch []int
+	for i, id := range ids {
+		post, ok := byID[id]
+		if !ok {
+			results[i].Err = pkgerrors.ErrNotFound
+			continue
+		}
+
+		if !actor.CanModify(post.AuthorID) {
+			results[i].Err = fmt.Errorf("%w: only the author of the post or an editor can modify it", pkgerrors.ErrPermissionDenied)
+			continue
+		}
+
+		valid = append(valid, i)
+		batch = append(batch, id)
+	}
+
+	if !partial && abortBatch(results) {
+		return results, nil
+	}
+
+	deleted, err := s.repo.DeleteBatch(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	isDeleted := make(map[int]bool, len(deleted))
+	for _, id := range deleted {
+		isDeleted[id] = true
+	}
+
+	// a post trashed concurrently is gone either way, it is only reported
+	for _, i := range valid {
+		if !isDeleted[ids[i]] {
+			results[i].Err = pkgerrors.ErrNotFound
+			continue
+		}
+
+		results[i].Post = &domain.Post{ID: ids[i]}
+	}
+
+	return results, nil
+}
+
+func (s *PostService) getBatch(ctx context.Context, ids []int) (map[int]*domain.Post, error) {
+	posts, err := s.repo.GetBatch(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*domain.Post, len(posts))
+	for _, post := range posts {
+		byID[post.ID] = post
+	}
+
+	return byID, nil
+}
+
+func validateBatchSize(n int) error {
+	if n == 0 {
+		return fmt.Errorf("%w: a batch must contain at least one item", pkgerrors.ErrInvalidArgument)
+	}
+
+	if n > domain.MaxBatchSize {
+		return fmt.Errorf("%w: a batch can contain at most %d items", pkgerrors.ErrInvalidArgument, domain.MaxBatchSize)
+	}
+
+	return nil
+}
+
+func newBatchResults(n int) []*domain.PostBatchResult {
+	results := make([]*domain.PostBatchResult, n)
+	for i := range results {
+		results[i] = &domain.PostBatchResult{Index: i}
+	}
+
+	return results
+}
+
+// abortBatch reports whether an item of the batch failed and, if so, marks
+// all the other items as aborted.
+func abortBatch(results []*domain.PostBatchResult) bool {
+	failed := false
+	for _, result := range results {
+		if result.Err != nil {
+			failed = true
+			break
+		}
+	}
+
+	if !failed {
+		return false
+	}
+
+	for _, result := range results {
+		if result.Err == nil {
+			result.Post = nil
+			result.Err = pkgerrors.ErrBatchAborted
+		}
+	}
+
+	return true
+}
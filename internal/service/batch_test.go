@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/domain"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+func postInput(title string) domain.PostInput {
+	return domain.PostInput{Title: title, Content: "Content", AuthorID: 1}
+}
+
+// assertResults checks every result against the post id or the error
+// expected at its index.
+func assertResults(t *testing.T, results []*domain.PostBatchResult, expected ...interface{}) {
+	t.Helper()
+
+	if !assert.Len(t, results, len(expected)) {
+		return
+	}
+
+	for i, want := range expected {
+		assert.Equal(t, i, results[i].Index)
+
+		switch want := want.(type) {
+		case int:
+			assert.NoError(t, results[i].Err, "item %d", i)
+			if assert.NotNil(t, results[i].Post, "item %d", i) {
+				assert.Equal(t, want, results[i].Post.ID, "item %d", i)
+			}
+		case error:
+			assert.ErrorIs(t, results[i].Err, want, "item %d", i)
+			assert.Nil(t, results[i].Post, "item %d", i)
+		}
+	}
+}
+
+func TestPostService_BatchCreate_Atomic(t *testing.T) {
+	s, posts := newPostService(t, nil)
+
+	// an invalid item aborts the batch before anything is written
+	posts.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).Times(0)
+
+	results, err := s.BatchCreate(asEditor(), []domain.PostInput{postInput("First"), postInput("T"), postInput("Third")}, false)
+
+	assert.NoError(t, err)
+	assertResults(t, results, pkgerrors.ErrBatchAborted, pkgerrors.ErrInvalidArgument, pkgerrors.ErrBatchAborted)
+}
+
+func TestPostService_BatchCreate_AtomicOneItemFails(t *testing.T) {
+	s, posts := newPostService(t, nil)
+
+	posts.EXPECT().CreateBatch(gomock.Any(), gomock.Len(3)).
+		Return(nil, &domain.BatchItemError{Index: 1, Err: pkgerrors.ErrNotFound})
+
+	results, err := s.BatchCreate(asEditor(), []domain.PostInput{postInput("First"), postInput("Second"), postInput("Third")}, false)
+
+	assert.NoError(t, err)
+	assertResults(t, results, pkgerrors.ErrBatchAborted, pkgerrors.ErrNotFound, pkgerrors.ErrBatchAborted)
+}
+
+func TestPostService_BatchCreate_Partial(t *testing.T) {
+	s, posts := newPostService(t, nil)
+
+	// the invalid item is left out, the others keep their place
+	posts.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inputs []*domain.PostInput) ([]*domain.Post, error) {
+		assert.Len(t, inputs, 2)
+		assert.Equal(t, "First", inputs[0].Title)
+		assert.Equal(t, "Third", inputs[1].Title)
+		return []*domain.Post{{ID: 10}, {ID: 11}}, nil
+	})
+
+	results, err := s.BatchCreate(asEditor(), []domain.PostInput{postInput("First"), postInput("T"), postInput("Third")}, true)
+
+	assert.NoError(t, err)
+	assertResults(t, results, 10, pkgerrors.ErrInvalidArgument, 11)
+}
+
+func TestPostService_BatchCreate_PartialFallsBackToCreate(t *testing.T) {
+	s, posts := newPostService(t, nil)
+
+	// item 1 of the batch is the third item of the request
+	gomock.InOrder(
+		posts.EXPECT().CreateBatch(gomock.Any(), gomock.Len(2)).
+			Return(nil, &domain.BatchItemError{Index: 1, Err: pkgerrors.ErrNotFound}),
+		posts.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&domain.Post{ID: 10}, nil),
+		posts.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, pkgerrors.ErrNotFound),
+	)
+
+	results, err := s.BatchCreate(asEditor(), []domain.PostInput{postInput("First"), postInput("T"), postInput("Third")}, true)
+
+	assert.NoError(t, err)
+	assertResults(t, results, 10, pkgerrors.ErrInvalidArgument, pkgerrors.ErrNotFound)
+}
+
+func TestPostService_BatchUpdate_Atomic(t *testing.T) {
+	s, posts := newPostService(t, nil)
+
+	posts.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id int) (*domain.Post, error) {
+		return &domain.Post{ID: id, AuthorID: 1}, nil
+	}).Times(3)
+	posts.EXPECT().UpdateBatch(gomock.Any(), gomock.Len(3)).
+		Return(nil, &domain.BatchItemError{Index: 2, Err: pkgerrors.ErrVersionConflict})
+
+	results, err := s.BatchUpdate(asEditor(), []domain.PostBatchUpdate{
+		{ID: 1, Input: domain.PostInput{Title: "First", Version: 1, Fields: []string{"title"}}},
+		{ID: 2, Input: domain.PostInput{Title: "Second", Version: 1, Fields: []string{"title"}}},
+		{ID: 3, Input: domain.PostInput{Title: "Third", Version: 1, Fields: []string{"title"}}},
+	}, false)
+
+	assert.NoError(t, err)
+	assertResults(t, results, pkgerrors.ErrBatchAborted, pkgerrors.ErrBatchAborted, pkgerrors.ErrVersionConflict)
+}
+
+func TestPostService_BatchUpdate_Partial(t *testing.T) {
+	s, posts := newPostService(t, nil)
+
+	posts.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, id int) (*domain.Post, error) {
+		return &domain.Post{ID: id, AuthorID: 1}, nil
+	}).Times(2)
+	gomock.InOrder(
+		posts.EXPECT().Update(gomock.Any(), 1, gomock.Any()).Return(&domain.Post{ID: 1, Version: 2}, nil),
+		posts.EXPECT().Update(gomock.Any(), 3, gomock.Any()).Return(nil, pkgerrors.ErrVersionConflict),
+	)
+	posts.EXPECT().UpdateBatch(gomock.Any(), gomock.Any()).Times(0)
+
+	results, err := s.BatchUpdate(asEditor(), []domain.PostBatchUpdate{
+		{ID: 1, Input: domain.PostInput{Title: "First", Version: 1, Fields: []string{"title"}}},
+		{ID: 2, Input: domain.PostInput{Title: "Second", Fields: []string{"title"}}},
+		{ID: 3, Input: domain.PostInput{Title: "Third", Version: 1, Fields: []string{"title"}}},
+	}, true)
+
+	assert.NoError(t, err)
+	assertResults(t, results, 1, pkgerrors.ErrInvalidArgument, pkgerrors.ErrVersionConflict)
+}
+
+func TestPostService_BatchDelete(t *testing.T) {
+	t.Run("atomic", func(t *testing.T) {
+		s, posts := newPostService(t, nil)
+
+		posts.EXPECT().GetBatch(gomock.Any(), []int{1, 2, 3}).Return([]*domain.Post{{ID: 1}, {ID: 3}}, nil)
+		posts.EXPECT().DeleteBatch(gomock.Any(), gomock.Any()).Times(0)
+
+		results, err := s.BatchDelete(asEditor(), []int{1, 2, 3}, false)
+
+		assert.NoError(t, err)
+		assertResults(t, results, pkgerrors.ErrBatchAborted, pkgerrors.ErrNotFound, pkgerrors.ErrBatchAborted)
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		s, posts := newPostService(t, nil)
+
+		// post 3 is trashed concurrently
+		posts.EXPECT().GetBatch(gomock.Any(), []int{1, 2, 3}).Return([]*domain.Post{{ID: 1}, {ID: 3}}, nil)
+		posts.EXPECT().DeleteBatch(gomock.Any(), []int{1, 3}).Return([]int{1}, nil)
+
+		results, err := s.BatchDelete(asEditor(), []int{1, 2, 3}, true)
+
+		assert.NoError(t, err)
+		assertResults(t, results, 1, pkgerrors.ErrNotFound, pkgerrors.ErrNotFound)
+	})
+}
+
+func TestPostService_BatchCreate_Size(t *testing.T) {
+	s, _ := newPostService(t, nil)
+
+	_, err := s.BatchCreate(asEditor(), nil, false)
+	assert.ErrorIs(t, err, pkgerrors.ErrInvalidArgument)
+
+	_, err = s.BatchCreate(asEditor(), make([]domain.PostInput, domain.MaxBatchSize+1), false)
+	assert.ErrorIs(t, err, pkgerrors.ErrInvalidArgument)
+}
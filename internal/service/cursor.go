@@ -0,0 +1,35 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+// Page tokens are base64-encoded JSON cursors. Clients must treat them as
+// opaque strings, so the format may change at any time.
+
+func encodePageToken(cursor domain.Cursor) string {
+	b, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodePageToken(token string) (*domain.Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.ErrInvalidPageToken
+	}
+
+	var cursor domain.Cursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return nil, errors.ErrInvalidPageToken
+	}
+
+	if cursor.ID <= 0 || !cursor.Sort.Field.Valid() {
+		return nil, errors.ErrInvalidPageToken
+	}
+
+	return &cursor, nil
+}
@@ -4,24 +4,58 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/sirupsen/logrus"
+
+	"github.com/kokhno-nikolay/news/config"
 	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/auth"
+	"github.com/kokhno-nikolay/news/internal/logging"
 	"github.com/kokhno-nikolay/news/internal/repository"
+	"github.com/kokhno-nikolay/news/pkg/diff"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 200
+
+	maxSearchQueryLength = 256
+
+	maxPostTags = 20
 )
 
+// statusSources lists, for every status, the statuses a post may be moved to
+// it from.
+var statusSources = map[domain.PostStatus][]domain.PostStatus{
+	domain.PostStatusInReview:  {domain.PostStatusDraft},
+	domain.PostStatusPublished: {domain.PostStatusDraft, domain.PostStatusInReview},
+	domain.PostStatusDraft:     {domain.PostStatusPublished, domain.PostStatusArchived},
+	domain.PostStatusArchived:  {domain.PostStatusDraft, domain.PostStatusInReview, domain.PostStatusPublished},
+}
+
 type PostService struct {
-	repo repository.Posts
+	repo           repository.Posts
+	notifier       repository.PostNotifier
+	searchLanguage string
 }
 
-func NewPostsService(repo repository.Posts) *PostService {
+func NewPostsService(repo repository.Posts, notifier repository.PostNotifier, cfg *config.Config) *PostService {
 	return &PostService{
-		repo: repo,
+		repo:           repo,
+		notifier:       notifier,
+		searchLanguage: cfg.SearchLanguage,
 	}
 }
 
-func (s *PostService) Get(ctx context.Context, id int) (*domain.Post, error) {
+// Get returns a published post. Unpublished posts are only returned when
+// asked for and only to those who can modify them, everyone else gets
+// ErrNotFound as if the post did not exist.
+func (s *PostService) Get(ctx context.Context, id int, includeUnpublished bool) (*domain.Post, error) {
 	if id < 0 {
-		return nil, errors.New("<id> must be greater than or equal to zero")
+		return nil, fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
 	}
 
 	post, err := s.repo.Get(ctx, id)
@@ -29,39 +63,211 @@ func (s *PostService) Get(ctx context.Context, id int) (*domain.Post, error) {
 		return nil, err
 	}
 
+	if err := checkVisible(ctx, post, includeUnpublished); err != nil {
+		return nil, err
+	}
+
 	return post, nil
 }
 
-func (s *PostService) List(ctx context.Context, limit int) ([]*domain.Post, error) {
-	// ось тут все залежить від бізнес-завдання, звичайно ж у реальному проекті швидше за все використовувалася б пагінація
+// checkVisible hides unpublished posts from everyone who cannot modify them.
+func checkVisible(ctx context.Context, post *domain.Post, includeUnpublished bool) error {
+	if post.Status == domain.PostStatusPublished {
+		return nil
+	}
+
+	if !includeUnpublished {
+		return pkgerrors.ErrNotFound
+	}
+
+	actor, ok := auth.ActorFromContext(ctx)
+	if !ok {
+		return pkgerrors.ErrUnauthenticated
+	}
+
+	if !actor.CanModify(post.AuthorID) {
+		return pkgerrors.ErrNotFound
+	}
+
+	return nil
+}
 
-	posts, err := s.repo.List(ctx, nil)
+func (s *PostService) List(ctx context.Context, input domain.PostListInput) (*domain.PostList, error) {
+	if input.Limit < 0 {
+		return nil, fmt.Errorf("%w: <limit> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if input.Offset < 0 {
+		return nil, fmt.Errorf("%w: <offset> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	if err := validatePostFilter(&input.Filter); err != nil {
+		return nil, err
+	}
+
+	if err := scopePostFilter(ctx, &input.Filter); err != nil {
+		return nil, err
+	}
+
+	sort, err := parsePostSort(input.SortBy, input.SortOrder)
 	if err != nil {
 		return nil, err
 	}
 
-	return posts, nil
+	// one extra row tells us whether there is another page in the requested direction
+	params := domain.PostListParams{
+		Filter: input.Filter,
+		Sort:   sort,
+		Limit:  limit + 1,
+	}
+	if input.PageToken != "" {
+		cursor, err := decodePageToken(input.PageToken)
+		if err != nil {
+			return nil, err
+		}
+
+		// a token is only meaningful in the order it was issued for
+		if cursor.Sort != sort {
+			return nil, pkgerrors.ErrInvalidPageToken
+		}
+		params.Cursor = cursor
+	} else {
+		params.Offset = input.Offset
+	}
+
+	posts, err := s.repo.List(ctx, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	backward := params.Cursor != nil && params.Cursor.Backward
+	hasMore := len(posts) > limit
+	if hasMore {
+		if backward {
+			posts = posts[1:]
+		} else {
+			posts = posts[:limit]
+		}
+	}
+
+	list := &domain.PostList{Posts: posts}
+
+	if len(posts) > 0 {
+		first, last := posts[0], posts[len(posts)-1]
+
+		if backward || hasMore {
+			list.NextPageToken = encodePageToken(domain.NewCursor(last, sort, false))
+		}
+
+		if backward && hasMore || !backward && (params.Cursor != nil || params.Offset > 0) {
+			list.PrevPageToken = encodePageToken(domain.NewCursor(first, sort, true))
+		}
+	}
+
+	if input.IncludeTotal {
+		total, err := s.repo.Count(ctx, &input.Filter)
+		if err != nil {
+			return nil, err
+		}
+		list.TotalCount = total
+	}
+
+	return list, nil
+}
+
+func (s *PostService) Search(ctx context.Context, input domain.SearchInput) ([]*domain.SearchResult, error) {
+	query := strings.TrimSpace(input.Query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: <q> must not be empty", pkgerrors.ErrInvalidArgument)
+	}
+
+	if len(query) > maxSearchQueryLength {
+		return nil, fmt.Errorf("%w: <q> must be at most %d characters long", pkgerrors.ErrInvalidArgument, maxSearchQueryLength)
+	}
+
+	if input.Limit < 0 {
+		return nil, fmt.Errorf("%w: <limit> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if input.Offset < 0 {
+		return nil, fmt.Errorf("%w: <offset> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	results, err := s.repo.Search(ctx, &domain.SearchParams{
+		Query:    query,
+		Language: s.searchLanguage,
+		Limit:    limit,
+		Offset:   input.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
 }
 
 func (s *PostService) Create(ctx context.Context, input domain.PostInput) (*domain.Post, error) {
-	if err := validatePostInput(&input); err != nil {
+	actor, ok := auth.ActorFromContext(ctx)
+	if !ok {
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+
+	if err := prepareCreate(actor, &input); err != nil {
 		return nil, err
 	}
+	input.SearchLanguage = s.searchLanguage
 
 	post, err := s.repo.Create(ctx, &input)
 	if err != nil {
 		return nil, err
 	}
 
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"post_id":   post.ID,
+		"author_id": post.AuthorID,
+	}).Info("post created")
+
 	return post, nil
 }
 
-func (s *PostService) Update(ctx context.Context, id int, input domain.PostInput) (*domain.Post, error) {
-	if id < 0 {
-		return nil, errors.New("<id> must be greater than or equal to zero")
+// prepareCreate fills in the author of a new post and validates it.
+func prepareCreate(actor *auth.Actor, input *domain.PostInput) error {
+	if input.AuthorID == 0 {
+		input.AuthorID = actor.AuthorID
 	}
 
-	if err := validatePostInput(&input); err != nil {
+	if input.AuthorID <= 0 {
+		return fmt.Errorf("%w: <author_id> must be greater than zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if !actor.CanModify(input.AuthorID) {
+		return fmt.Errorf("%w: only editors can post on behalf of another author", pkgerrors.ErrPermissionDenied)
+	}
+	input.EditedBy = actor.AuthorID
+
+	return validatePostInput(input)
+}
+
+// Update changes a post the caller has seen at input.Version, so concurrent
+// edits fail with ErrVersionConflict instead of overwriting each other.
+func (s *PostService) Update(ctx context.Context, id int, input domain.PostInput) (*domain.Post, error) {
+	if err := s.prepareUpdate(ctx, id, &input); err != nil {
 		return nil, err
 	}
 
@@ -70,35 +276,527 @@ func (s *PostService) Update(ctx context.Context, id int, input domain.PostInput
 		return nil, err
 	}
 
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"post_id": post.ID,
+		"version": post.Version,
+	}).Info("post updated")
+
 	return post, nil
 }
 
+// prepareUpdate validates an update and checks the caller may modify the
+// post.
+func (s *PostService) prepareUpdate(ctx context.Context, id int, input *domain.PostInput) error {
+	if id < 0 {
+		return fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if input.Version <= 0 {
+		return fmt.Errorf("%w: <version> is required, pass the version of the post being edited", pkgerrors.ErrInvalidArgument)
+	}
+
+	if err := applyUpdateMask(input); err != nil {
+		return err
+	}
+
+	if err := validatePostInput(input); err != nil {
+		return err
+	}
+
+	if _, err := s.authorize(ctx, id); err != nil {
+		return err
+	}
+
+	if actor, ok := auth.ActorFromContext(ctx); ok {
+		input.EditedBy = actor.AuthorID
+	}
+
+	return nil
+}
+
+// Delete moves a post to the trash, where it stays until it is restored,
+// purged or removed by the retention job.
 func (s *PostService) Delete(ctx context.Context, id int) (bool, error) {
+	if _, err := s.authorize(ctx, id); err != nil {
+		return false, err
+	}
+
 	success, err := s.repo.Delete(ctx, id)
 	if err != nil {
 		return false, err
 	}
 
+	if success {
+		logging.FromContext(ctx).WithField("post_id", id).Info("post moved to the trash")
+	}
+
+	return success, nil
+}
+
+// ListTrash returns trashed posts, most recently deleted first. Authors only
+// see their own posts.
+func (s *PostService) ListTrash(ctx context.Context, limit, offset int) ([]*domain.Post, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: <limit> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: <offset> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	actor, ok := auth.ActorFromContext(ctx)
+	if !ok {
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+
+	authorID := 0
+	if !actor.Role.AtLeast(auth.RoleEditor) {
+		authorID = actor.AuthorID
+	}
+
+	return s.repo.ListTrash(ctx, authorID, limit, offset)
+}
+
+// Restore takes a post out of the trash.
+func (s *PostService) Restore(ctx context.Context, id int) (*domain.Post, error) {
+	if err := s.authorizeTrashed(ctx, id); err != nil {
+		return nil, err
+	}
+
+	post, err := s.repo.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).WithField("post_id", id).Info("post restored from the trash")
+
+	return post, nil
+}
+
+// Purge permanently removes a post from the trash.
+func (s *PostService) Purge(ctx context.Context, id int) (bool, error) {
+	if err := s.authorizeTrashed(ctx, id); err != nil {
+		return false, err
+	}
+
+	success, err := s.repo.Purge(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if success {
+		logging.FromContext(ctx).WithField("post_id", id).Info("post purged")
+	}
+
 	return success, nil
 }
 
+// authorizeTrashed is authorize for posts in the trash.
+func (s *PostService) authorizeTrashed(ctx context.Context, id int) error {
+	actor, ok := auth.ActorFromContext(ctx)
+	if !ok {
+		return pkgerrors.ErrUnauthenticated
+	}
+
+	post, err := s.repo.GetTrashed(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !actor.CanModify(post.AuthorID) {
+		return fmt.Errorf("%w: only the author of the post or an editor can modify it", pkgerrors.ErrPermissionDenied)
+	}
+
+	return nil
+}
+
+// ListRevisions returns the change history of a post, newest first.
+func (s *PostService) ListRevisions(ctx context.Context, postID, limit, offset int) ([]*domain.PostRevision, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: <limit> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: <offset> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	if _, err := s.authorize(ctx, postID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListRevisions(ctx, postID, limit, offset)
+}
+
+func (s *PostService) GetRevision(ctx context.Context, postID, revision int) (*domain.PostRevision, error) {
+	if revision <= 0 {
+		return nil, fmt.Errorf("%w: <revision> must be greater than zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if _, err := s.authorize(ctx, postID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetRevision(ctx, postID, revision)
+}
+
+// DiffRevisions compares the title and content of two revisions line by line.
+func (s *PostService) DiffRevisions(ctx context.Context, postID, from, to int) (*domain.RevisionDiff, error) {
+	if from <= 0 || to <= 0 {
+		return nil, fmt.Errorf("%w: <from_revision> and <to_revision> must be greater than zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if _, err := s.authorize(ctx, postID); err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := s.repo.GetRevision(ctx, postID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, err := s.repo.GetRevision(ctx, postID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.RevisionDiff{
+		PostID:  postID,
+		From:    from,
+		To:      to,
+		Title:   diff.Lines(fromRevision.Title, toRevision.Title),
+		Content: diff.Lines(fromRevision.Content, toRevision.Content),
+	}, nil
+}
+
+// RestoreRevision brings back the text of an older revision. The restore is a
+// change like any other and is recorded as a new revision, categories and
+// tags are left as they are.
+func (s *PostService) RestoreRevision(ctx context.Context, postID, revision int) (*domain.Post, error) {
+	rev, err := s.GetRevision(ctx, postID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := s.repo.Get(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Update(ctx, postID, domain.PostInput{
+		Title:   rev.Title,
+		Content: rev.Content,
+		Version: post.Version,
+	})
+}
+
+// Submit sends a draft to review.
+func (s *PostService) Submit(ctx context.Context, id int) (*domain.Post, error) {
+	return s.transition(ctx, id, domain.PostStatusInReview)
+}
+
+// Publish makes a draft or a post in review public.
+func (s *PostService) Publish(ctx context.Context, id int) (*domain.Post, error) {
+	return s.transition(ctx, id, domain.PostStatusPublished)
+}
+
+// Unpublish takes a published or archived post back to drafts.
+func (s *PostService) Unpublish(ctx context.Context, id int) (*domain.Post, error) {
+	return s.transition(ctx, id, domain.PostStatusDraft)
+}
+
+func (s *PostService) Archive(ctx context.Context, id int) (*domain.Post, error) {
+	return s.transition(ctx, id, domain.PostStatusArchived)
+}
+
+func (s *PostService) transition(ctx context.Context, id int, to domain.PostStatus) (*domain.Post, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	post, err := s.authorize(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canTransition(post.Status, to) {
+		return nil, fmt.Errorf("%w: a %s post can not become %s", pkgerrors.ErrInvalidStatusTransition, post.Status, to)
+	}
+
+	updated, err := s.repo.UpdateStatus(ctx, id, post.Status, to)
+	if err != nil {
+		if errors.Is(err, pkgerrors.ErrNotFound) {
+			return nil, fmt.Errorf("%w: the post status was changed concurrently", pkgerrors.ErrInvalidStatusTransition)
+		}
+
+		return nil, err
+	}
+
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"post_id": id,
+		"from":    post.Status,
+		"to":      to,
+	}).Info("post status changed")
+
+	return updated, nil
+}
+
+// Schedule sets the moment a draft or a post in review gets published.
+func (s *PostService) Schedule(ctx context.Context, id int, publishAt time.Time) (*domain.Post, error) {
+	return s.schedule(ctx, id, &publishAt, false)
+}
+
+// Reschedule moves the publication of an already scheduled post.
+func (s *PostService) Reschedule(ctx context.Context, id int, publishAt time.Time) (*domain.Post, error) {
+	return s.schedule(ctx, id, &publishAt, true)
+}
+
+// CancelSchedule keeps a scheduled post from being published.
+func (s *PostService) CancelSchedule(ctx context.Context, id int) (*domain.Post, error) {
+	return s.schedule(ctx, id, nil, true)
+}
+
+func (s *PostService) schedule(ctx context.Context, id int, publishAt *time.Time, scheduled bool) (*domain.Post, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if publishAt != nil && !publishAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: <publish_at> must be in the future", pkgerrors.ErrInvalidArgument)
+	}
+
+	post, err := s.authorize(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canTransition(post.Status, domain.PostStatusPublished) {
+		return nil, fmt.Errorf("%w: a %s post can not be scheduled", pkgerrors.ErrInvalidStatusTransition, post.Status)
+	}
+
+	switch {
+	case scheduled && post.PublishAt == nil:
+		return nil, fmt.Errorf("%w: the post is not scheduled", pkgerrors.ErrInvalidStatusTransition)
+	case !scheduled && post.PublishAt != nil:
+		return nil, fmt.Errorf("%w: the post is already scheduled, reschedule it instead", pkgerrors.ErrInvalidStatusTransition)
+	}
+
+	updated, err := s.repo.SetPublishAt(ctx, id, post.Status, publishAt)
+	if err != nil {
+		if errors.Is(err, pkgerrors.ErrNotFound) {
+			return nil, fmt.Errorf("%w: the post status was changed concurrently", pkgerrors.ErrInvalidStatusTransition)
+		}
+
+		return nil, err
+	}
+
+	logging.FromContext(ctx).WithFields(logrus.Fields{
+		"post_id":    id,
+		"publish_at": publishAt,
+	}).Info("post schedule changed")
+
+	return updated, nil
+}
+
+func canTransition(from, to domain.PostStatus) bool {
+	for _, status := range statusSources[to] {
+		if status == from {
+			return true
+		}
+	}
+
+	return false
+}
+
+// authorize checks that the caller owns the post or is an editor and returns
+// the post.
+func (s *PostService) authorize(ctx context.Context, id int) (*domain.Post, error) {
+	actor, ok := auth.ActorFromContext(ctx)
+	if !ok {
+		return nil, pkgerrors.ErrUnauthenticated
+	}
+
+	post, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !actor.CanModify(post.AuthorID) {
+		return nil, fmt.Errorf("%w: only the author of the post or an editor can modify it", pkgerrors.ErrPermissionDenied)
+	}
+
+	return post, nil
+}
+
+// scopePostFilter limits a list to published posts unless other statuses are
+// asked for. Unpublished posts are listed to editors, authors only see their
+// own.
+func scopePostFilter(ctx context.Context, filter *domain.PostFilter) error {
+	if len(filter.Statuses) == 0 {
+		filter.Statuses = []domain.PostStatus{domain.PostStatusPublished}
+		return nil
+	}
+
+	onlyPublished := true
+	for _, status := range filter.Statuses {
+		if status != domain.PostStatusPublished {
+			onlyPublished = false
+		}
+	}
+
+	if onlyPublished {
+		return nil
+	}
+
+	actor, ok := auth.ActorFromContext(ctx)
+	if !ok {
+		return pkgerrors.ErrUnauthenticated
+	}
+
+	if actor.Role.AtLeast(auth.RoleEditor) {
+		return nil
+	}
+
+	if actor.Role != auth.RoleAuthor || filter.AuthorID != 0 && filter.AuthorID != actor.AuthorID {
+		return fmt.Errorf("%w: unpublished posts are only listed to their authors and editors", pkgerrors.ErrPermissionDenied)
+	}
+
+	filter.AuthorID = actor.AuthorID
+
+	return nil
+}
+
+func validatePostFilter(filter *domain.PostFilter) error {
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return fmt.Errorf("%w: <created_from> must be before <created_to>", pkgerrors.ErrInvalidArgument)
+	}
+
+	if filter.UpdatedFrom != nil && filter.UpdatedTo != nil && !filter.UpdatedFrom.Before(*filter.UpdatedTo) {
+		return fmt.Errorf("%w: <updated_from> must be before <updated_to>", pkgerrors.ErrInvalidArgument)
+	}
+
+	for i, status := range filter.Statuses {
+		status = domain.PostStatus(strings.ToLower(string(status)))
+		if !status.Valid() {
+			return fmt.Errorf("%w: %q, must be one of draft, in_review, published, archived", pkgerrors.ErrInvalidStatus, filter.Statuses[i])
+		}
+		filter.Statuses[i] = status
+	}
+
+	return nil
+}
+
+func parsePostSort(sortBy, sortOrder string) (domain.PostSort, error) {
+	sort := domain.PostSort{Field: domain.SortByCreatedAt, Desc: true}
+
+	if sortBy != "" {
+		sort.Field = domain.SortField(strings.ToLower(sortBy))
+		if !sort.Field.Valid() {
+			return sort, fmt.Errorf("%w: %q, must be one of created_at, updated_at, title", pkgerrors.ErrInvalidSortField, sortBy)
+		}
+	}
+
+	switch strings.ToLower(sortOrder) {
+	case "":
+		// titles read naturally from A to Z, dates from newest to oldest
+		sort.Desc = sort.Field != domain.SortByTitle
+	case "asc":
+		sort.Desc = false
+	case "desc":
+		sort.Desc = true
+	default:
+		return sort, fmt.Errorf("%w: %q, must be asc or desc", pkgerrors.ErrInvalidSortOrder, sortOrder)
+	}
+
+	return sort, nil
+}
+
+// applyUpdateMask checks the fields an update is limited to. Lists named in
+// the mask are replaced even when empty, lists left out keep their links.
+func applyUpdateMask(input *domain.PostInput) error {
+	if len(input.Fields) == 0 {
+		return nil
+	}
+
+	for _, field := range input.Fields {
+		switch field {
+		case domain.PostFieldTitle, domain.PostFieldContent, domain.PostFieldCategoryIDs, domain.PostFieldTags:
+		default:
+			return fmt.Errorf("%w: unknown field %q, must be one of title, content, category_ids, tags", pkgerrors.ErrInvalidUpdateMask, field)
+		}
+	}
+
+	if !input.Changes(domain.PostFieldCategoryIDs) {
+		input.CategoryIDs = nil
+	} else if input.CategoryIDs == nil {
+		input.CategoryIDs = []int{}
+	}
+
+	if !input.Changes(domain.PostFieldTags) {
+		input.Tags = nil
+	} else if input.Tags == nil {
+		input.Tags = []string{}
+	}
+
+	return nil
+}
+
 // ось тут також все залежить від бізнес завдання, просто додав пару кейсів для тестів
 // реальну валідацію обговорював би з продуктами
 func validatePostInput(input *domain.PostInput) error {
-	if len(input.Title) < 3 {
-		return fmt.Errorf("title must be at least 3 characters long")
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return err
 	}
+	input.Tags = tags
+
+	if input.CategoryIDs != nil {
+		seen := make(map[int]bool, len(input.CategoryIDs))
+		categoryIDs := make([]int, 0, len(input.CategoryIDs))
+		for _, id := range input.CategoryIDs {
+			if id <= 0 {
+				return fmt.Errorf("%w: <category_ids> must be greater than zero", pkgerrors.ErrInvalidArgument)
+			}
 
-	if len(input.Title) > 100 {
-		return fmt.Errorf("title must be at most 100 characters long")
+			if !seen[id] {
+				seen[id] = true
+				categoryIDs = append(categoryIDs, id)
+			}
+		}
+		input.CategoryIDs = categoryIDs
 	}
 
-	if len(input.Content) < 3 {
-		return fmt.Errorf("content must be at least 3 characters long")
+	if input.Changes(domain.PostFieldTitle) {
+		if len(input.Title) < 3 {
+			return fmt.Errorf("%w: title must be at least 3 characters long", pkgerrors.ErrInvalidArgument)
+		}
+
+		if len(input.Title) > 100 {
+			return fmt.Errorf("%w: title must be at most 100 characters long", pkgerrors.ErrInvalidArgument)
+		}
 	}
 
-	if len(input.Content) > 500 {
-		return fmt.Errorf("content must be at most 500 characters long")
+	if input.Changes(domain.PostFieldContent) {
+		if len(input.Content) < 3 {
+			return fmt.Errorf("%w: content must be at least 3 characters long", pkgerrors.ErrInvalidArgument)
+		}
+
+		if len(input.Content) > 500 {
+			return fmt.Errorf("%w: content must be at most 500 characters long", pkgerrors.ErrInvalidArgument)
+		}
 	}
 
 	return nil
@@ -0,0 +1,249 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/auth"
+	"github.com/kokhno-nikolay/news/internal/repository"
+	mock_repository "github.com/kokhno-nikolay/news/internal/repository/mocks"
+	"github.com/kokhno-nikolay/news/internal/service"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+func newPostService(t *testing.T, notifier repository.PostNotifier) (*service.PostService, *mock_repository.MockPosts) {
+	posts := mock_repository.NewMockPosts(gomock.NewController(t))
+
+	return service.NewPostsService(posts, notifier, &config.Config{}), posts
+}
+
+func asEditor() context.Context {
+	return auth.WithActor(context.Background(), &auth.Actor{Role: auth.RoleEditor, AuthorID: 1})
+}
+
+func TestPostService_Update_Mask(t *testing.T) {
+	post := &domain.Post{ID: 1, Title: "Title", Content: "Content", Version: 3}
+
+	tests := []struct {
+		name        string
+		input       domain.PostInput
+		err         error
+		tags        []string
+		categoryIDs []int
+	}{
+		{
+			name:  "unknown path",
+			input: domain.PostInput{Version: 3, Fields: []string{"title", "author_id"}},
+			err:   pkgerrors.ErrInvalidUpdateMask,
+		},
+		{
+			// the title is left out of the mask, it is neither validated nor changed
+			name:  "only masked fields are validated",
+			input: domain.PostInput{Content: "New content", Version: 3, Fields: []string{"content"}},
+		},
+		{
+			name:  "masked fields are validated",
+			input: domain.PostInput{Title: "T", Content: "New content", Version: 3, Fields: []string{"title", "content"}},
+			err:   pkgerrors.ErrInvalidArgument,
+		},
+		{
+			name:  "tags in the mask without a list are cleared",
+			input: domain.PostInput{Version: 3, Fields: []string{"tags"}},
+			tags:  []string{},
+		},
+		{
+			name:  "tags in the mask are set",
+			input: domain.PostInput{Tags: []string{"Go", " go ", "news"}, Version: 3, Fields: []string{"tags"}},
+			tags:  []string{"Go", "news"},
+		},
+		{
+			name:  "tags out of the mask are kept",
+			input: domain.PostInput{Title: "New title", Tags: []string{"go"}, CategoryIDs: []int{1}, Version: 3, Fields: []string{"title"}},
+		},
+		{
+			name:        "category ids in the mask without a list are cleared",
+			input:       domain.PostInput{Version: 3, Fields: []string{"category_ids"}},
+			categoryIDs: []int{},
+		},
+		{
+			name:  "without a mask empty links are kept",
+			input: domain.PostInput{Title: "New title", Content: "New content", Version: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, posts := newPostService(t, nil)
+
+			if tt.err == nil {
+				posts.EXPECT().Get(gomock.Any(), 1).Return(post, nil)
+				posts.EXPECT().Update(gomock.Any(), 1, gomock.Any()).DoAndReturn(func(_ context.Context, _ int, input *domain.PostInput) (*domain.Post, error) {
+					assert.Equal(t, tt.tags, input.Tags)
+					assert.Equal(t, tt.categoryIDs, input.CategoryIDs)
+					return post, nil
+				})
+			}
+
+			_, err := s.Update(asEditor(), 1, tt.input)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPostService_Transitions(t *testing.T) {
+	statuses := []domain.PostStatus{
+		domain.PostStatusDraft,
+		domain.PostStatusInReview,
+		domain.PostStatusPublished,
+		domain.PostStatusArchived,
+	}
+
+	allowed := map[domain.PostStatus][]domain.PostStatus{
+		domain.PostStatusDraft:     {domain.PostStatusInReview, domain.PostStatusPublished, domain.PostStatusArchived},
+		domain.PostStatusInReview:  {domain.PostStatusPublished, domain.PostStatusArchived},
+		domain.PostStatusPublished: {domain.PostStatusDraft, domain.PostStatusArchived},
+		domain.PostStatusArchived:  {domain.PostStatusDraft},
+	}
+
+	move := map[domain.PostStatus]func(s *service.PostService, ctx context.Context, id int) (*domain.Post, error){
+		domain.PostStatusDraft:     (*service.PostService).Unpublish,
+		domain.PostStatusInReview:  (*service.PostService).Submit,
+		domain.PostStatusPublished: (*service.PostService).Publish,
+		domain.PostStatusArchived:  (*service.PostService).Archive,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			ok := false
+			for _, status := range allowed[from] {
+				ok = ok || status == to
+			}
+
+			t.Run(string(from)+" to "+string(to), func(t *testing.T) {
+				s, posts := newPostService(t, nil)
+
+				posts.EXPECT().Get(gomock.Any(), 1).Return(&domain.Post{ID: 1, Status: from}, nil)
+				if ok {
+					posts.EXPECT().UpdateStatus(gomock.Any(), 1, from, to).Return(&domain.Post{ID: 1, Status: to}, nil)
+				}
+
+				post, err := move[to](s, asEditor(), 1)
+
+				if ok {
+					assert.NoError(t, err)
+					assert.Equal(t, to, post.Status)
+				} else {
+					assert.ErrorIs(t, err, pkgerrors.ErrInvalidStatusTransition)
+				}
+			})
+		}
+	}
+}
+
+func TestPostService_Transition_Concurrent(t *testing.T) {
+	s, posts := newPostService(t, nil)
+
+	posts.EXPECT().Get(gomock.Any(), 1).Return(&domain.Post{ID: 1, Status: domain.PostStatusDraft}, nil)
+	posts.EXPECT().UpdateStatus(gomock.Any(), 1, domain.PostStatusDraft, domain.PostStatusPublished).Return(nil, pkgerrors.ErrNotFound)
+
+	_, err := s.Publish(asEditor(), 1)
+
+	assert.ErrorIs(t, err, pkgerrors.ErrInvalidStatusTransition)
+}
+
+func TestPostService_Get_Visibility(t *testing.T) {
+	actors := map[string]*auth.Actor{
+		"anonymous":    nil,
+		"reader":       {Role: auth.RoleReader},
+		"author":       {Role: auth.RoleAuthor, AuthorID: 1},
+		"other author": {Role: auth.RoleAuthor, AuthorID: 2},
+		"editor":       {Role: auth.RoleEditor},
+	}
+
+	tests := []struct {
+		status             domain.PostStatus
+		includeUnpublished bool
+		actor              string
+		err                error
+	}{
+		{domain.PostStatusPublished, false, "anonymous", nil},
+		{domain.PostStatusPublished, false, "reader", nil},
+		{domain.PostStatusPublished, true, "other author", nil},
+		{domain.PostStatusPublished, true, "editor", nil},
+
+		// unpublished posts are only returned when asked for
+		{domain.PostStatusDraft, false, "anonymous", pkgerrors.ErrNotFound},
+		{domain.PostStatusDraft, false, "author", pkgerrors.ErrNotFound},
+		{domain.PostStatusDraft, false, "editor", pkgerrors.ErrNotFound},
+
+		// and then to those who can modify them
+		{domain.PostStatusDraft, true, "anonymous", pkgerrors.ErrUnauthenticated},
+		{domain.PostStatusDraft, true, "reader", pkgerrors.ErrNotFound},
+		{domain.PostStatusDraft, true, "author", nil},
+		{domain.PostStatusDraft, true, "other author", pkgerrors.ErrNotFound},
+		{domain.PostStatusDraft, true, "editor", nil},
+		{domain.PostStatusInReview, true, "author", nil},
+		{domain.PostStatusInReview, true, "other author", pkgerrors.ErrNotFound},
+		{domain.PostStatusArchived, true, "reader", pkgerrors.ErrNotFound},
+		{domain.PostStatusArchived, true, "editor", nil},
+	}
+
+	for _, tt := range tests {
+		name := fmt.Sprintf("%s post, include unpublished %t, %s", tt.status, tt.includeUnpublished, tt.actor)
+		t.Run(name, func(t *testing.T) {
+			s, posts := newPostService(t, nil)
+
+			posts.EXPECT().Get(gomock.Any(), 1).Return(&domain.Post{ID: 1, AuthorID: 1, Status: tt.status}, nil)
+
+			ctx := context.Background()
+			if actor := actors[tt.actor]; actor != nil {
+				ctx = auth.WithActor(ctx, actor)
+			}
+
+			post, err := s.Get(ctx, 1, tt.includeUnpublished)
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Nil(t, post)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, post.ID)
+			}
+		})
+	}
+}
+
+func TestPostService_Create_SearchLanguage(t *testing.T) {
+	posts := mock_repository.NewMockPosts(gomock.NewController(t))
+	s := service.NewPostsService(posts, nil, &config.Config{SearchLanguage: "simple"})
+	input := domain.PostInput{Title: "Title", Content: "Content"}
+
+	posts.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input *domain.PostInput) (*domain.Post, error) {
+		assert.Equal(t, "simple", input.SearchLanguage)
+		return &domain.Post{ID: 1}, nil
+	})
+	posts.EXPECT().CreateBatch(gomock.Any(), gomock.Len(2)).DoAndReturn(func(_ context.Context, inputs []*domain.PostInput) ([]*domain.Post, error) {
+		for _, input := range inputs {
+			assert.Equal(t, "simple", input.SearchLanguage)
+		}
+		return []*domain.Post{{ID: 2}, {ID: 3}}, nil
+	})
+
+	_, err := s.Create(asEditor(), input)
+	assert.NoError(t, err)
+
+	// imports go through the batch
+	_, err = s.Import(asEditor(), []domain.PostImport{{Input: input}, {Input: input}})
+	assert.NoError(t, err)
+}
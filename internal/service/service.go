@@ -1,13 +1,23 @@
 package service
 
-import "github.com/kokhno-nikolay/news/internal/repository"
+import (
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/internal/repository"
+	"github.com/kokhno-nikolay/news/internal/webhooks"
+)
 
 type Service struct {
 	PostService
+	TaxonomyService
+	AuthorService
+	WebhookService
 }
 
-func NewService(repo *repository.Repository) *Service {
+func NewService(repo *repository.Repository, cfg *config.Config) *Service {
 	return &Service{
-		PostService: *NewPostsService(repo.Posts),
+		PostService:     *NewPostsService(repo.Posts, repo.Notifier, cfg),
+		TaxonomyService: *NewTaxonomyService(repo.Categories, repo.Tags),
+		AuthorService:   *NewAuthorService(repo.Authors),
+		WebhookService:  *NewWebhookService(repo.Webhooks, webhooks.NewSender(cfg.WebhookTimeout)),
 	}
 }
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/repository"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+	"github.com/kokhno-nikolay/news/pkg/slug"
+)
+
+const (
+	maxCategoryNameLength = 100
+	maxTagNameLength      = 50
+)
+
+type TaxonomyService struct {
+	categories repository.Categories
+	tags       repository.Tags
+}
+
+func NewTaxonomyService(categories repository.Categories, tags repository.Tags) *TaxonomyService {
+	return &TaxonomyService{
+		categories: categories,
+		tags:       tags,
+	}
+}
+
+func (s *TaxonomyService) GetCategory(ctx context.Context, id int) (*domain.Category, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	return s.categories.Get(ctx, id)
+}
+
+func (s *TaxonomyService) ListCategories(ctx context.Context) ([]*domain.Category, error) {
+	return s.categories.List(ctx)
+}
+
+func (s *TaxonomyService) CreateCategory(ctx context.Context, input domain.CategoryInput) (*domain.Category, error) {
+	if err := normalizeTaxonomyInput(&input.Name, &input.Slug, maxCategoryNameLength); err != nil {
+		return nil, err
+	}
+
+	return s.categories.Create(ctx, &input)
+}
+
+func (s *TaxonomyService) UpdateCategory(ctx context.Context, id int, input domain.CategoryInput) (*domain.Category, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if err := normalizeTaxonomyInput(&input.Name, &input.Slug, maxCategoryNameLength); err != nil {
+		return nil, err
+	}
+
+	return s.categories.Update(ctx, id, &input)
+}
+
+func (s *TaxonomyService) DeleteCategory(ctx context.Context, id int) (bool, error) {
+	return s.categories.Delete(ctx, id)
+}
+
+func (s *TaxonomyService) GetTag(ctx context.Context, id int) (*domain.Tag, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	return s.tags.Get(ctx, id)
+}
+
+func (s *TaxonomyService) ListTags(ctx context.Context, limit, offset int) ([]*domain.Tag, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: <limit> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: <offset> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	return s.tags.List(ctx, limit, offset)
+}
+
+func (s *TaxonomyService) CreateTag(ctx context.Context, input domain.TagInput) (*domain.Tag, error) {
+	if err := normalizeTaxonomyInput(&input.Name, &input.Slug, maxTagNameLength); err != nil {
+		return nil, err
+	}
+
+	return s.tags.Create(ctx, &input)
+}
+
+func (s *TaxonomyService) UpdateTag(ctx context.Context, id int, input domain.TagInput) (*domain.Tag, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if err := normalizeTaxonomyInput(&input.Name, &input.Slug, maxTagNameLength); err != nil {
+		return nil, err
+	}
+
+	return s.tags.Update(ctx, id, &input)
+}
+
+func (s *TaxonomyService) DeleteTag(ctx context.Context, id int) (bool, error) {
+	return s.tags.Delete(ctx, id)
+}
+
+// normalizeTaxonomyInput trims the name and derives the slug from it when
+// the caller did not choose one.
+func normalizeTaxonomyInput(name, slugValue *string, maxLength int) error {
+	*name = strings.TrimSpace(*name)
+	if *name == "" {
+		return fmt.Errorf("%w: name must not be empty", pkgerrors.ErrInvalidArgument)
+	}
+
+	if len(*name) > maxLength {
+		return fmt.Errorf("%w: name must be at most %d characters long", pkgerrors.ErrInvalidArgument, maxLength)
+	}
+
+	if *slugValue == "" {
+		*slugValue = *name
+	}
+
+	*slugValue = slug.Make(*slugValue)
+	if *slugValue == "" {
+		return fmt.Errorf("%w: slug must contain at least one letter or digit", pkgerrors.ErrInvalidArgument)
+	}
+
+	if len(*slugValue) > maxLength {
+		return fmt.Errorf("%w: slug must be at most %d characters long", pkgerrors.ErrInvalidArgument, maxLength)
+	}
+
+	return nil
+}
+
+// normalizeTags trims tag names and drops the ones that map to an already
+// seen slug, so "Go" and "go" end up as a single tag.
+func normalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	if len(tags) > maxPostTags {
+		return nil, fmt.Errorf("%w: a post can have at most %d tags", pkgerrors.ErrInvalidArgument, maxPostTags)
+	}
+
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+
+	for _, name := range tags {
+		name = strings.TrimSpace(name)
+		if len(name) > maxTagNameLength {
+			return nil, fmt.Errorf("%w: tag must be at most %d characters long", pkgerrors.ErrInvalidArgument, maxTagNameLength)
+		}
+
+		s := slug.Make(name)
+		if s == "" {
+			return nil, fmt.Errorf("%w: tag %q must contain at least one letter or digit", pkgerrors.ErrInvalidArgument, name)
+		}
+
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		normalized = append(normalized, name)
+	}
+
+	return normalized, nil
+}
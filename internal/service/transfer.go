@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kokhno-nikolay/news/domain"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+// exportPageSize is the number of posts Export reads at a time.
+const exportPageSize = 200
+
+// Import creates a chunk of at most domain.MaxBatchSize imported posts. Every
+// record is created on its own merits, the results report the failed ones.
+// Posts are created as drafts and then moved to their status, a post that
+// can not be moved stays a draft and is reported as failed.
+func (s *PostService) Import(ctx context.Context, records []domain.PostImport) ([]*domain.PostBatchResult, error) {
+	results := newBatchResults(len(records))
+
+	var valid []int
+	var inputs []domain.PostInput
+	for i, record := range records {
+		if record.Status == "" {
+			records[i].Status = domain.PostStatusDraft
+		}
+
+		if !records[i].Status.Valid() {
+			results[i].Err = fmt.Errorf("%w: %q", pkgerrors.ErrInvalidStatus, record.Status)
+			continue
+		}
+
+		valid = append(valid, i)
+		inputs = append(inputs, record.Input)
+	}
+
+	if len(inputs) == 0 {
+		return results, nil
+	}
+
+	created, err := s.BatchCreate(ctx, inputs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for n, i := range valid {
+		results[i].Post, results[i].Err = created[n].Post, created[n].Err
+		if results[i].Err != nil || records[i].Status == domain.PostStatusDraft {
+			continue
+		}
+
+		post, err := s.repo.UpdateStatus(ctx, results[i].Post.ID, domain.PostStatusDraft, records[i].Status)
+		if err != nil {
+			results[i].Err = fmt.Errorf("post %d was imported as a draft: %w", results[i].Post.ID, err)
+			continue
+		}
+		results[i].Post = post
+	}
+
+	return results, nil
+}
+
+// Export pages through the posts matching the filter, oldest first, and
+// hands every page to fn along with the number of posts the filter matched
+// when the export started. Only one page is held in memory at a time.
+func (s *PostService) Export(ctx context.Context, filter domain.PostFilter, fn func(posts []*domain.Post, total int) error) error {
+	if err := validatePostFilter(&filter); err != nil {
+		return err
+	}
+
+	if err := scopePostFilter(ctx, &filter); err != nil {
+		return err
+	}
+
+	total, err := s.repo.Count(ctx, &filter)
+	if err != nil {
+		return err
+	}
+
+	params := domain.PostListParams{
+		Filter: filter,
+		Sort:   domain.PostSort{Field: domain.SortByCreatedAt},
+		Limit:  exportPageSize,
+	}
+
+	for {
+		posts, err := s.repo.List(ctx, &params)
+		if err != nil {
+			return err
+		}
+
+		if len(posts) == 0 {
+			return nil
+		}
+
+		if err := fn(posts, total); err != nil {
+			return err
+		}
+
+		if len(posts) < exportPageSize {
+			return nil
+		}
+
+		cursor := domain.NewCursor(posts[len(posts)-1], params.Sort, false)
+		params.Cursor = &cursor
+	}
+}
@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/domain"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+func TestPostService_Import(t *testing.T) {
+	s, posts := newPostService(t, nil)
+
+	records := []domain.PostImport{
+		{Input: postInput("Draft")},
+		{Input: postInput("Unknown"), Status: "deleted"},
+		{Input: postInput("Published"), Status: domain.PostStatusPublished},
+		{Input: postInput("Archived"), Status: domain.PostStatusArchived},
+		{Input: postInput("T"), Status: domain.PostStatusPublished},
+	}
+
+	// records with a valid status are created as drafts in one batch, the
+	// invalid ones keep their index
+	posts.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, inputs []*domain.PostInput) ([]*domain.Post, error) {
+		assert.Len(t, inputs, 3)
+		return []*domain.Post{
+			{ID: 10, Status: domain.PostStatusDraft},
+			{ID: 11, Status: domain.PostStatusDraft},
+			{ID: 12, Status: domain.PostStatusDraft},
+		}, nil
+	})
+
+	// and then moved to their status
+	posts.EXPECT().UpdateStatus(gomock.Any(), 11, domain.PostStatusDraft, domain.PostStatusPublished).
+		Return(&domain.Post{ID: 11, Status: domain.PostStatusPublished}, nil)
+	posts.EXPECT().UpdateStatus(gomock.Any(), 12, domain.PostStatusDraft, domain.PostStatusArchived).
+		Return(nil, pkgerrors.ErrNotFound)
+
+	results, err := s.Import(asEditor(), records)
+
+	assert.NoError(t, err)
+	assertResults(t, results[:3], 10, pkgerrors.ErrInvalidStatus, 11)
+	assert.Equal(t, domain.PostStatusPublished, results[2].Post.Status)
+
+	// a post that could not be moved is kept as a draft
+	assert.Equal(t, 3, results[3].Index)
+	assert.ErrorIs(t, results[3].Err, pkgerrors.ErrNotFound)
+	assert.Contains(t, results[3].Err.Error(), "post 12 was imported as a draft")
+	assert.Equal(t, 12, results[3].Post.ID)
+
+	assert.Equal(t, 4, results[4].Index)
+	assert.ErrorIs(t, results[4].Err, pkgerrors.ErrInvalidArgument)
+}
+
+func TestPostService_Import_NothingValid(t *testing.T) {
+	s, posts := newPostService(t, nil)
+
+	posts.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).Times(0)
+
+	results, err := s.Import(asEditor(), []domain.PostImport{{Input: postInput("Title"), Status: "deleted"}})
+
+	assert.NoError(t, err)
+	assertResults(t, results, pkgerrors.ErrInvalidStatus)
+}
+
+// postPage returns n posts with ids from first on.
+func postPage(first, n int) []*domain.Post {
+	page := make([]*domain.Post, n)
+	for i := range page {
+		page[i] = &domain.Post{ID: first + i}
+	}
+
+	return page
+}
+
+func TestPostService_Export(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages []int
+	}{
+		{"last page partial", []int{200, 200, 50}},
+		{"last page full", []int{200, 200, 0}},
+		{"nothing", []int{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, posts := newPostService(t, nil)
+
+			total := 0
+			for _, n := range tt.pages {
+				total += n
+			}
+
+			posts.EXPECT().Count(gomock.Any(), gomock.Any()).Return(total, nil)
+
+			// every page starts after the last post of the previous one
+			var calls []*gomock.Call
+			next := 1
+			for _, n := range tt.pages {
+				after, page := next-1, postPage(next, n)
+				calls = append(calls, posts.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, params *domain.PostListParams) ([]*domain.Post, error) {
+					assert.Equal(t, 200, params.Limit)
+					assert.Equal(t, domain.SortByCreatedAt, params.Sort.Field)
+					assert.False(t, params.Sort.Desc)
+					if after == 0 {
+						assert.Nil(t, params.Cursor)
+					} else if assert.NotNil(t, params.Cursor) {
+						assert.Equal(t, after, params.Cursor.ID)
+					}
+					return page, nil
+				}))
+				next += n
+			}
+			gomock.InOrder(calls...)
+
+			var exported []int
+			err := s.Export(context.Background(), domain.PostFilter{}, func(page []*domain.Post, n int) error {
+				assert.Equal(t, total, n)
+				assert.NotEmpty(t, page)
+				for _, post := range page {
+					exported = append(exported, post.ID)
+				}
+				return nil
+			})
+
+			assert.NoError(t, err)
+			assert.Len(t, exported, total)
+			for i, id := range exported {
+				assert.Equal(t, i+1, id)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/auth"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+const (
+	// watchPageSize is the number of events Watch reads at a time.
+	watchPageSize = 100
+	// gapRecheckInterval is how often Watch checks whether a gap in the
+	// event ids is settled while nothing is notified.
+	gapRecheckInterval = time.Second
+)
+
+// Watch calls fn with every post event recorded after afterID until ctx is
+// done, a zero afterID starts from the events recorded from now on. Events
+// are filtered like Get: posts the caller can not see are left out, and a
+// post that was public but is hidden since is reported as deleted.
+//
+// Event ids are taken before the writer commits, so a smaller id may become
+// visible after a larger one. Watch stops at such a gap until the writer
+// is done, events are never skipped and always come in order of their ids.
+func (s *PostService) Watch(ctx context.Context, afterID int64, includeUnpublished bool, fn func(*domain.PostEvent) error) error {
+	if afterID < 0 {
+		return fmt.Errorf("%w: <after_id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	first, last, err := s.repo.EventBounds(ctx)
+	if err != nil {
+		return err
+	}
+
+	if afterID == 0 {
+		afterID = last
+	} else if first > 0 && afterID < first-1 {
+		return pkgerrors.ErrEventsExpired
+	}
+
+	var gap *eventGap
+
+	for {
+		// taken before reading, so an event recorded in between still wakes
+		// the loop up
+		changed := s.notifier.Changed()
+
+		events, err := s.repo.ListEvents(ctx, afterID, watchPageSize)
+		if err != nil {
+			return err
+		}
+
+		n := contiguous(afterID, events)
+		if n < len(events) && gap != nil && gap.settled && gap.after == lastEventID(afterID, events[:n]) {
+			// nothing can fill the gap anymore, the id was rolled back
+			n += 1 + contiguous(events[n].ID, events[n+1:])
+			gap = nil
+		}
+
+		if err := s.sendEvents(ctx, events[:n], includeUnpublished, fn); err != nil {
+			return err
+		}
+
+		afterID = lastEventID(afterID, events[:n])
+
+		if n < len(events) {
+			// the events past the gap wait until the writer that took the
+			// missing id commits or rolls back
+			xmin, xmax, err := s.repo.EventSnapshot(ctx)
+			if err != nil {
+				return err
+			}
+
+			if gap == nil || gap.after != afterID {
+				gap = &eventGap{after: afterID, xmax: xmax}
+			} else if xmin >= gap.xmax {
+				gap.settled = true
+				continue
+			}
+
+			// a rollback is not notified
+			timer := time.NewTimer(gapRecheckInterval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+			case <-changed:
+				timer.Stop()
+			case <-timer.C:
+			}
+			continue
+		}
+
+		if len(events) == watchPageSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
+
+// eventGap is an id missing between the events read, taken by a writer
+// that had not committed yet.
+type eventGap struct {
+	// after is the id of the last event before the gap.
+	after int64
+	// xmax is the first transaction that started after the gap was seen,
+	// once all those before are over the gap is there for good.
+	xmax int64
+	// settled is set once the gap is there for good, it is skipped on the
+	// next read should it still be there.
+	settled bool
+}
+
+// contiguous returns how many of the events follow afterID without a gap.
+func contiguous(afterID int64, events []*domain.PostEvent) int {
+	for i, event := range events {
+		if event.ID != afterID+int64(i)+1 {
+			return i
+		}
+	}
+
+	return len(events)
+}
+
+func lastEventID(afterID int64, events []*domain.PostEvent) int64 {
+	if len(events) == 0 {
+		return afterID
+	}
+
+	return events[len(events)-1].ID
+}
+
+func (s *PostService) sendEvents(ctx context.Context, events []*domain.PostEvent, includeUnpublished bool, fn func(*domain.PostEvent) error) error {
+	var ids []int
+	for _, event := range events {
+		if event.Type != domain.PostDeleted {
+			ids = append(ids, event.PostID)
+		}
+	}
+
+	var posts map[int]*domain.Post
+	if len(ids) > 0 {
+		var err error
+		if posts, err = s.getBatch(ctx, ids); err != nil {
+			return err
+		}
+	}
+
+	for _, event := range events {
+		if event.Type != domain.PostDeleted {
+			post, ok := posts[event.PostID]
+			if ok && checkVisible(ctx, post, includeUnpublished) == nil {
+				event.Post = post
+				if err := fn(event); err != nil {
+					return err
+				}
+				continue
+			}
+
+			// the post is gone or hidden since, those who could see it are
+			// told it was deleted
+			event.Type = domain.PostDeleted
+		}
+
+		if !eventVisible(ctx, event, includeUnpublished) {
+			continue
+		}
+
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// eventVisible reports whether the caller could see the post at the time of
+// the event.
+func eventVisible(ctx context.Context, event *domain.PostEvent, includeUnpublished bool) bool {
+	if event.Published {
+		return true
+	}
+
+	if !includeUnpublished {
+		return false
+	}
+
+	actor, ok := auth.ActorFromContext(ctx)
+
+	return ok && actor.CanModify(event.AuthorID)
+}
@@ -0,0 +1,114 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/domain"
+	mock_repository "github.com/kokhno-nikolay/news/internal/repository/mocks"
+	"github.com/kokhno-nikolay/news/internal/service"
+)
+
+var errStop = errors.New("stop")
+
+// newWatchService returns a post service whose notifier ends every wait right
+// away, the reads of the tests drive the watch.
+func newWatchService(t *testing.T) (*service.PostService, *mock_repository.MockPosts) {
+	notifier := mock_repository.NewMockPostNotifier(gomock.NewController(t))
+
+	changed := make(chan struct{})
+	close(changed)
+	notifier.EXPECT().Changed().Return((<-chan struct{})(changed)).AnyTimes()
+
+	s, posts := newPostService(t, notifier)
+	posts.EXPECT().GetBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, ids []int) ([]*domain.Post, error) {
+		var batch []*domain.Post
+		for _, id := range ids {
+			batch = append(batch, &domain.Post{ID: id, Status: domain.PostStatusPublished})
+		}
+		return batch, nil
+	}).AnyTimes()
+
+	return s, posts
+}
+
+func event(id int64) *domain.PostEvent {
+	return &domain.PostEvent{ID: id, Type: domain.PostCreated, PostID: int(id), Published: true}
+}
+
+// watchUntil collects the ids of the events sent until the one with the
+// given id.
+func watchUntil(s *service.PostService, afterID, lastID int64) ([]int64, error) {
+	var ids []int64
+	err := s.Watch(context.Background(), afterID, false, func(event *domain.PostEvent) error {
+		ids = append(ids, event.ID)
+		if event.ID == lastID {
+			return errStop
+		}
+		return nil
+	})
+
+	return ids, err
+}
+
+func TestPostService_Watch_HoldsGapUntilWriterCommits(t *testing.T) {
+	s, posts := newWatchService(t)
+
+	// writer A takes id 2, writer B takes id 3 and commits first
+	gomock.InOrder(
+		posts.EXPECT().EventBounds(gomock.Any()).Return(int64(1), int64(3), nil),
+		posts.EXPECT().ListEvents(gomock.Any(), int64(1), gomock.Any()).Return([]*domain.PostEvent{event(3)}, nil),
+		posts.EXPECT().EventSnapshot(gomock.Any()).Return(int64(100), int64(102), nil),
+		// writer A commits
+		posts.EXPECT().ListEvents(gomock.Any(), int64(1), gomock.Any()).Return([]*domain.PostEvent{event(2), event(3)}, nil),
+	)
+
+	ids, err := watchUntil(s, 1, 3)
+
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, []int64{2, 3}, ids)
+}
+
+func TestPostService_Watch_SkipsGapOnceSettled(t *testing.T) {
+	s, posts := newWatchService(t)
+
+	// writer A takes id 2 and rolls back after writer B committed id 3
+	gomock.InOrder(
+		posts.EXPECT().EventBounds(gomock.Any()).Return(int64(1), int64(3), nil),
+		posts.EXPECT().ListEvents(gomock.Any(), int64(1), gomock.Any()).Return([]*domain.PostEvent{event(3)}, nil),
+		posts.EXPECT().EventSnapshot(gomock.Any()).Return(int64(100), int64(102), nil),
+		// writer A is still running
+		posts.EXPECT().ListEvents(gomock.Any(), int64(1), gomock.Any()).Return([]*domain.PostEvent{event(3)}, nil),
+		posts.EXPECT().EventSnapshot(gomock.Any()).Return(int64(101), int64(103), nil),
+		// writer A rolled back
+		posts.EXPECT().ListEvents(gomock.Any(), int64(1), gomock.Any()).Return([]*domain.PostEvent{event(3)}, nil),
+		posts.EXPECT().EventSnapshot(gomock.Any()).Return(int64(102), int64(104), nil),
+		posts.EXPECT().ListEvents(gomock.Any(), int64(1), gomock.Any()).Return([]*domain.PostEvent{event(3), event(4)}, nil),
+	)
+
+	ids, err := watchUntil(s, 1, 4)
+
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, []int64{3, 4}, ids)
+}
+
+func TestPostService_Watch_SendsEventsBeforeGap(t *testing.T) {
+	s, posts := newWatchService(t)
+
+	// ids 2 and 4 are committed, 3 is not yet
+	gomock.InOrder(
+		posts.EXPECT().EventBounds(gomock.Any()).Return(int64(1), int64(4), nil),
+		posts.EXPECT().ListEvents(gomock.Any(), int64(1), gomock.Any()).Return([]*domain.PostEvent{event(2), event(4)}, nil),
+		posts.EXPECT().EventSnapshot(gomock.Any()).Return(int64(100), int64(103), nil),
+		posts.EXPECT().ListEvents(gomock.Any(), int64(2), gomock.Any()).Return([]*domain.PostEvent{event(3), event(4)}, nil),
+	)
+
+	ids, err := watchUntil(s, 1, 4)
+
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, []int64{2, 3, 4}, ids)
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/auth"
+	"github.com/kokhno-nikolay/news/internal/repository"
+	"github.com/kokhno-nikolay/news/internal/webhooks"
+	pkgerrors "github.com/kokhno-nikolay/news/pkg/errors"
+)
+
+const (
+	maxWebhookURLLength = 2048
+	webhookSecretLength = 32
+)
+
+type WebhookService struct {
+	repo   repository.Webhooks
+	sender *webhooks.Sender
+}
+
+func NewWebhookService(repo repository.Webhooks, sender *webhooks.Sender) *WebhookService {
+	return &WebhookService{
+		repo:   repo,
+		sender: sender,
+	}
+}
+
+// List returns the subscriptions without their secrets.
+func (s *WebhookService) List(ctx context.Context, limit, offset int) ([]*domain.WebhookSubscription, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: <limit> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: <offset> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	subscriptions, err := s.repo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subscription := range subscriptions {
+		subscription.Secret = ""
+	}
+
+	return subscriptions, nil
+}
+
+// Create subscribes a URL to the given events, post.published when there
+// are none. The returned subscription carries the generated secret, which
+// can not be read afterwards.
+func (s *WebhookService) Create(ctx context.Context, input domain.WebhookInput) (*domain.WebhookSubscription, error) {
+	if err := validateWebhookInput(&input); err != nil {
+		return nil, err
+	}
+
+	if actor, ok := auth.ActorFromContext(ctx); ok {
+		input.CreatedBy = actor.AuthorID
+	}
+
+	secret := make([]byte, webhookSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	input.Secret = hex.EncodeToString(secret)
+
+	return s.repo.Create(ctx, &input)
+}
+
+// Delete removes a subscription, deliveries still queued are dropped.
+func (s *WebhookService) Delete(ctx context.Context, id int) (bool, error) {
+	if id < 0 {
+		return false, fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// Test sends a webhook.test delivery to the subscription right away and
+// returns its outcome. Test deliveries are logged but not retried.
+func (s *WebhookService) Test(ctx context.Context, id int) (*domain.WebhookDelivery, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	subscription, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(webhooks.Payload{
+		Event:      domain.WebhookTest,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.repo.CreateDelivery(ctx, id, domain.WebhookTest, payload, webhooks.ClaimLease)
+	if err != nil {
+		return nil, err
+	}
+	delivery.URL = subscription.URL
+	delivery.Secret = subscription.Secret
+
+	delivery.ResponseStatus, err = s.sender.Send(ctx, delivery)
+	if err != nil {
+		delivery.Status = domain.WebhookDeliveryFailed
+		delivery.LastError = err.Error()
+
+		return delivery, s.repo.MarkFailed(ctx, delivery.ID, delivery.ResponseStatus, delivery.LastError, nil)
+	}
+
+	now := time.Now()
+	delivery.Status = domain.WebhookDeliveryDelivered
+	delivery.DeliveredAt = &now
+
+	return delivery, s.repo.MarkDelivered(ctx, delivery.ID, delivery.ResponseStatus)
+}
+
+// ListDeliveries returns the delivery log of a subscription, latest first.
+func (s *WebhookService) ListDeliveries(ctx context.Context, id, limit, offset int) ([]*domain.WebhookDelivery, error) {
+	if id < 0 {
+		return nil, fmt.Errorf("%w: <id> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: <limit> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: <offset> must be greater than or equal to zero", pkgerrors.ErrInvalidArgument)
+	}
+
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	if _, err := s.repo.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListDeliveries(ctx, id, limit, offset)
+}
+
+func validateWebhookInput(input *domain.WebhookInput) error {
+	input.URL = strings.TrimSpace(input.URL)
+
+	if len(input.URL) > maxWebhookURLLength {
+		return fmt.Errorf("%w: url must be at most %d characters long", pkgerrors.ErrInvalidArgument, maxWebhookURLLength)
+	}
+
+	u, err := url.Parse(input.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", pkgerrors.ErrInvalidArgument)
+	}
+
+	if len(input.Events) == 0 {
+		input.Events = []domain.WebhookEvent{domain.WebhookPostPublished}
+	}
+
+	seen := make(map[domain.WebhookEvent]bool, len(input.Events))
+	events := input.Events[:0]
+	for _, event := range input.Events {
+		if !event.Valid() {
+			return fmt.Errorf("%w: unknown webhook event %q", pkgerrors.ErrInvalidArgument, event)
+		}
+
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	input.Events = events
+
+	return nil
+}
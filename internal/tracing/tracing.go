@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+
+	"github.com/kokhno-nikolay/news/config"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+
+	serviceName = "news"
+)
+
+// Init sets up the W3C trace context propagator and a tracer provider
+// sending spans to the exporter selected by cfg.TracingExporter. The
+// returned func flushes the spans still buffered and stops the provider.
+func Init(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+	// set up without an exporter too, so the trace context of a caller
+	// reaches the services called by this one
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, cfg *config.Config) (sdktrace.SpanExporter, error) {
+	switch cfg.TracingExporter {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterStdout:
+		return stdouttrace.New()
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.TracingOTLPEndpoint)}
+		if cfg.TracingOTLPInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		return otlptracegrpc.New(ctx, opts...)
+	}
+
+	return nil, fmt.Errorf("unknown tracing exporter %q, must be one of %s, %s, %s", cfg.TracingExporter, ExporterOTLP, ExporterStdout, ExporterNone)
+}
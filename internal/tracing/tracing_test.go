@@ -0,0 +1,41 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/internal/tracing"
+)
+
+func TestInit(t *testing.T) {
+	t.Cleanup(func() { otel.SetTracerProvider(trace.NewNoopTracerProvider()) })
+	ctx := context.Background()
+
+	t.Run("none", func(t *testing.T) {
+		shutdown, err := tracing.Init(ctx, &config.Config{TracingExporter: tracing.ExporterNone})
+		assert.NoError(t, err)
+		assert.NoError(t, shutdown(ctx))
+
+		// the trace context of a caller is passed on without an exporter
+		carrier := propagation.MapCarrier{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+		ctx := otel.GetTextMapPropagator().Extract(ctx, carrier)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.SpanContextFromContext(ctx).TraceID().String())
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		shutdown, err := tracing.Init(ctx, &config.Config{TracingExporter: tracing.ExporterStdout, TracingSampleRatio: 1})
+		assert.NoError(t, err)
+		assert.NoError(t, shutdown(ctx))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := tracing.Init(ctx, &config.Config{TracingExporter: "jaeger"})
+		assert.Error(t, err)
+	})
+}
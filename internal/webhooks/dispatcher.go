@@ -0,0 +1,93 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/repository"
+)
+
+// Payload is the JSON body of a delivery. EventID is the id of the post
+// event, receivers tell repeated deliveries apart by it. Post is the post
+// as it was right after the change, or right before it was deleted.
+type Payload struct {
+	Event      domain.WebhookEvent `json:"event"`
+	EventID    int64               `json:"event_id,omitempty"`
+	OccurredAt time.Time           `json:"occurred_at"`
+	Post       json.RawMessage     `json:"post,omitempty"`
+}
+
+// Dispatcher is the outbox publisher of webhooks: it queues a delivery of
+// every post event to the subscriptions of its webhook event and wakes the
+// pool up.
+type Dispatcher struct {
+	repo repository.Webhooks
+	pool *Pool
+}
+
+func NewDispatcher(repo repository.Webhooks, pool *Pool) *Dispatcher {
+	return &Dispatcher{
+		repo: repo,
+		pool: pool,
+	}
+}
+
+func (d *Dispatcher) Publish(ctx context.Context, event *domain.PostEvent) error {
+	webhookEvent, ok := EventOf(event)
+	if !ok {
+		return nil
+	}
+
+	payload, err := json.Marshal(Payload{
+		Event:      webhookEvent,
+		EventID:    event.ID,
+		OccurredAt: event.CreatedAt,
+		Post:       event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	queued, err := d.repo.Enqueue(ctx, event.ID, webhookEvent, payload)
+	if err != nil {
+		return err
+	}
+
+	if queued > 0 {
+		d.pool.Notify()
+	}
+
+	return nil
+}
+
+// EventOf returns the webhook event of a post event. Changes of posts that
+// were never public have none.
+func EventOf(event *domain.PostEvent) (domain.WebhookEvent, bool) {
+	published := event.Status == domain.PostStatusPublished
+	wasPublished := event.PreviousStatus == domain.PostStatusPublished
+
+	switch event.Type {
+	case domain.PostCreated:
+		if published {
+			return domain.WebhookPostPublished, true
+		}
+	case domain.PostUpdated:
+		switch {
+		case published && wasPublished:
+			return domain.WebhookPostUpdated, true
+		case published:
+			return domain.WebhookPostPublished, true
+		case wasPublished:
+			return domain.WebhookPostUnpublished, true
+		}
+	case domain.PostDeleted:
+		// Published tells whether the post was public before the deletion
+		if event.Published {
+			return domain.WebhookPostDeleted, true
+		}
+	}
+
+	return "", false
+}
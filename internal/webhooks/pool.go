@@ -0,0 +1,170 @@
+package webhooks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/domain"
+	"github.com/kokhno-nikolay/news/internal/repository"
+	"github.com/kokhno-nikolay/news/pkg/backoff"
+)
+
+const (
+	defaultWorkers     = 4
+	defaultInterval    = 5 * time.Second
+	defaultBatchSize   = 100
+	defaultMaxAttempts = 10
+
+	// ClaimLease hides claimed deliveries from other workers while they are
+	// being sent, it must outlast the timeout of a delivery.
+	ClaimLease = 5 * time.Minute
+
+	minBackoff = 10 * time.Second
+	maxBackoff = 6 * time.Hour
+)
+
+// Pool sends queued deliveries from a fixed number of workers. It is woken
+// up by Notify and also checks every interval for deliveries due to be
+// retried. Replicas claim disjoint deliveries, so it is safe to run on every
+// one of them.
+type Pool struct {
+	repo        repository.Webhooks
+	sender      *Sender
+	workers     int
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	wake        chan struct{}
+}
+
+func NewPool(repo repository.Webhooks, sender *Sender, cfg *config.Config) *Pool {
+	p := &Pool{
+		repo:        repo,
+		sender:      sender,
+		workers:     cfg.WebhookWorkers,
+		interval:    cfg.WebhookInterval,
+		batchSize:   cfg.WebhookBatchSize,
+		maxAttempts: cfg.WebhookMaxAttempts,
+		wake:        make(chan struct{}, 1),
+	}
+
+	if p.workers <= 0 {
+		p.workers = defaultWorkers
+	}
+
+	if p.interval <= 0 {
+		p.interval = defaultInterval
+	}
+
+	if p.batchSize <= 0 {
+		p.batchSize = defaultBatchSize
+	}
+
+	if p.batchSize > repository.MaxLimit {
+		p.batchSize = repository.MaxLimit
+	}
+
+	if p.maxAttempts <= 0 {
+		p.maxAttempts = defaultMaxAttempts
+	}
+
+	return p
+}
+
+// Notify tells the pool that deliveries have been queued.
+func (p *Pool) Notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends deliveries until ctx is done, then waits for the workers to
+// finish the deliveries at hand.
+func (p *Pool) Run(ctx context.Context) {
+	jobs := make(chan *domain.WebhookDelivery)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(p.workers)
+	for i := 0; i < p.workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for delivery := range jobs {
+				p.deliver(ctx, delivery)
+			}
+		}()
+	}
+
+	defer func() {
+		close(jobs)
+		wg.Wait()
+	}()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.dispatchPending(ctx, jobs)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Pool) dispatchPending(ctx context.Context, jobs chan<- *domain.WebhookDelivery) {
+	for ctx.Err() == nil {
+		deliveries, err := p.repo.ClaimDeliveries(ctx, p.batchSize, ClaimLease)
+		if err != nil {
+			logrus.WithError(err).Error("webhooks: claiming deliveries")
+			return
+		}
+
+		for _, delivery := range deliveries {
+			select {
+			case <-ctx.Done():
+				// the rest is claimed again once the lease is over
+				return
+			case jobs <- delivery:
+			}
+		}
+
+		if len(deliveries) < p.batchSize {
+			return
+		}
+	}
+}
+
+func (p *Pool) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	status, err := p.sender.Send(ctx, delivery)
+	if err != nil {
+		p.markFailed(ctx, delivery, status, err)
+		return
+	}
+
+	if err := p.repo.MarkDelivered(ctx, delivery.ID, status); err != nil {
+		logrus.WithError(err).WithField("delivery_id", delivery.ID).Error("webhooks: marking delivery delivered")
+	}
+}
+
+func (p *Pool) markFailed(ctx context.Context, delivery *domain.WebhookDelivery, status int, cause error) {
+	var retryAt *time.Time
+	if delivery.Attempts < p.maxAttempts {
+		at := time.Now().Add(backoff.Exponential(delivery.Attempts, minBackoff, maxBackoff))
+		retryAt = &at
+	} else {
+		logrus.WithError(cause).WithFields(logrus.Fields{"delivery_id": delivery.ID, "attempts": delivery.Attempts}).Warn("webhooks: giving delivery up")
+	}
+
+	if err := p.repo.MarkFailed(ctx, delivery.ID, status, cause.Error(), retryAt); err != nil {
+		logrus.WithError(err).WithField("delivery_id", delivery.ID).Error("webhooks: marking delivery failed")
+	}
+}
@@ -0,0 +1,100 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/config"
+	"github.com/kokhno-nikolay/news/domain"
+	mock_repository "github.com/kokhno-nikolay/news/internal/repository/mocks"
+)
+
+// newPool returns a pool sending to a server that accepts deliveries to
+// /ok and fails all others.
+func newPool(t *testing.T) (*Pool, *mock_repository.MockWebhooks, string) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ok" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	repo := mock_repository.NewMockWebhooks(gomock.NewController(t))
+	cfg := &config.Config{WebhookWorkers: 2, WebhookBatchSize: 2, WebhookMaxAttempts: 3}
+
+	return NewPool(repo, NewSender(time.Second), cfg), repo, srv.URL
+}
+
+func TestPool_Deliver(t *testing.T) {
+	const reason = "webhook responded with 500 Internal Server Error"
+
+	t.Run("delivered", func(t *testing.T) {
+		p, repo, url := newPool(t)
+
+		repo.EXPECT().MarkDelivered(gomock.Any(), int64(1), http.StatusNoContent).Return(nil)
+
+		p.deliver(context.Background(), &domain.WebhookDelivery{ID: 1, URL: url + "/ok", Attempts: 1})
+	})
+
+	t.Run("retried with a growing delay", func(t *testing.T) {
+		p, repo, url := newPool(t)
+
+		repo.EXPECT().MarkFailed(gomock.Any(), int64(1), http.StatusInternalServerError, reason, gomock.Not(gomock.Nil())).
+			DoAndReturn(func(_ context.Context, _ int64, _ int, _ string, retryAt *time.Time) error {
+				// the second attempt waits twice the first delay
+				assert.WithinDuration(t, time.Now().Add(2*minBackoff), *retryAt, time.Second)
+				return nil
+			})
+
+		p.deliver(context.Background(), &domain.WebhookDelivery{ID: 1, URL: url + "/fail", Attempts: 2})
+	})
+
+	t.Run("given up after max attempts", func(t *testing.T) {
+		p, repo, url := newPool(t)
+
+		repo.EXPECT().MarkFailed(gomock.Any(), int64(1), http.StatusInternalServerError, reason, gomock.Nil()).Return(nil)
+
+		p.deliver(context.Background(), &domain.WebhookDelivery{ID: 1, URL: url + "/fail", Attempts: 3})
+	})
+}
+
+func TestPool_Run(t *testing.T) {
+	p, repo, url := newPool(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the run ends once both deliveries of the full first page are handled
+	var handled atomic.Int32
+	done := func(context.Context, int64, int) { handled.Add(1) }
+	failed := func(context.Context, int64, int, string, *time.Time) { handled.Add(1) }
+	repo.EXPECT().MarkDelivered(gomock.Any(), int64(1), http.StatusNoContent).Do(done).Return(nil)
+	repo.EXPECT().MarkFailed(gomock.Any(), int64(2), http.StatusInternalServerError, gomock.Any(), gomock.Not(gomock.Nil())).Do(failed).Return(nil)
+
+	gomock.InOrder(
+		repo.EXPECT().ClaimDeliveries(gomock.Any(), 2, ClaimLease).Return([]*domain.WebhookDelivery{
+			{ID: 1, URL: url + "/ok", Attempts: 1},
+			{ID: 2, URL: url + "/fail", Attempts: 1},
+		}, nil),
+		// skipped when the run is over before the next page is claimed
+		repo.EXPECT().ClaimDeliveries(gomock.Any(), 2, ClaimLease).Return(nil, nil).MaxTimes(1),
+	)
+
+	go func() {
+		for handled.Load() < 2 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	p.Run(ctx)
+}
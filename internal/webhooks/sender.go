@@ -0,0 +1,53 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kokhno-nikolay/news/domain"
+)
+
+// Sender posts deliveries to the URL of their subscription.
+type Sender struct {
+	client *http.Client
+}
+
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send posts the signed payload of the delivery and returns the response
+// status, zero when there was no response. Any status but 2xx fails the
+// delivery.
+func (s *Sender) Send(ctx context.Context, delivery *domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(delivery.Event))
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, time.Now(), delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// drained so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
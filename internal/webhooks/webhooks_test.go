@@ -0,0 +1,84 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/domain"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"event":"post.published"}`)
+	header := Sign("secret", now, body)
+
+	assert.NoError(t, Verify("secret", header, body, time.Minute, now))
+	assert.ErrorIs(t, Verify("other", header, body, time.Minute, now), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", header, []byte(`{}`), time.Minute, now), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", header, body, time.Minute, now.Add(time.Hour)), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", "v1=abc", body, time.Minute, now), ErrInvalidSignature)
+}
+
+func TestEventOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		event domain.PostEvent
+		want  domain.WebhookEvent
+	}{
+		{"created draft", domain.PostEvent{Type: domain.PostCreated, Status: domain.PostStatusDraft}, ""},
+		{"restored published", domain.PostEvent{Type: domain.PostCreated, Status: domain.PostStatusPublished}, domain.WebhookPostPublished},
+		{"published", domain.PostEvent{Type: domain.PostUpdated, Status: domain.PostStatusPublished, PreviousStatus: domain.PostStatusInReview}, domain.WebhookPostPublished},
+		{"edited published", domain.PostEvent{Type: domain.PostUpdated, Status: domain.PostStatusPublished, PreviousStatus: domain.PostStatusPublished}, domain.WebhookPostUpdated},
+		{"unpublished", domain.PostEvent{Type: domain.PostUpdated, Status: domain.PostStatusArchived, PreviousStatus: domain.PostStatusPublished}, domain.WebhookPostUnpublished},
+		{"edited draft", domain.PostEvent{Type: domain.PostUpdated, Status: domain.PostStatusDraft, PreviousStatus: domain.PostStatusDraft}, ""},
+		{"deleted published", domain.PostEvent{Type: domain.PostDeleted, Published: true}, domain.WebhookPostDeleted},
+		{"deleted draft", domain.PostEvent{Type: domain.PostDeleted}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := EventOf(&tt.event)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want != "", ok)
+		})
+	}
+}
+
+func TestSender_Send(t *testing.T) {
+	status := http.StatusNoContent
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "post.published", r.Header.Get(EventHeader))
+		assert.Equal(t, "8", r.Header.Get(DeliveryHeader))
+		assert.NoError(t, Verify("secret", r.Header.Get(SignatureHeader), body, time.Minute, time.Now()))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sender := NewSender(time.Second)
+	delivery := &domain.WebhookDelivery{
+		ID:      8,
+		Event:   domain.WebhookPostPublished,
+		Payload: []byte(`{"event":"post.published"}`),
+		URL:     srv.URL,
+		Secret:  "secret",
+	}
+
+	got, err := sender.Send(context.Background(), delivery)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, got)
+
+	status = http.StatusGone
+	got, err = sender.Send(context.Background(), delivery)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusGone, got)
+}
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS posts_set_updated_at ON posts;
+DROP FUNCTION IF EXISTS set_updated_at();
+
+DROP INDEX IF EXISTS posts_title_id_idx;
+DROP INDEX IF EXISTS posts_updated_at_id_idx;
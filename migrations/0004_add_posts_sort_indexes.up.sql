@@ -0,0 +1,14 @@
+CREATE INDEX IF NOT EXISTS posts_updated_at_id_idx ON posts (updated_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS posts_title_id_idx ON posts (title, id);
+
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER posts_set_updated_at
+    BEFORE UPDATE ON posts
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,12 @@
+-- search_language is kept per row, so switching the configuration is a matter
+-- of updating the column (and SEARCH_LANGUAGE) instead of a schema change.
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS search_language REGCONFIG NOT NULL DEFAULT 'english';
+
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector(search_language, coalesce(title, '')), 'A') ||
+        setweight(to_tsvector(search_language, coalesce(content, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS posts_search_vector_idx ON posts USING GIN (search_vector);
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS categories
+(
+    id         SERIAL NOT NULL PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    slug       VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tags
+(
+    id         SERIAL NOT NULL PRIMARY KEY,
+    name       VARCHAR(50) NOT NULL,
+    slug       VARCHAR(50) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS post_categories
+(
+    post_id     INT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+    category_id INT NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (post_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS post_categories_category_id_idx ON post_categories (category_id);
+
+CREATE TABLE IF NOT EXISTS post_tags
+(
+    post_id INT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+    tag_id  INT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (post_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS post_tags_tag_id_idx ON post_tags (tag_id);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS authors
+(
+    id         SERIAL NOT NULL PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    email      VARCHAR(255) NOT NULL UNIQUE,
+    bio        TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER authors_set_updated_at
+    BEFORE UPDATE ON authors
+    FOR EACH ROW
+    EXECUTE FUNCTION set_updated_at();
+
+-- posts written before authors existed stay anonymous
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS author_id INT REFERENCES authors (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS posts_author_id_idx ON posts (author_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS posts_status_created_at_idx;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS status;
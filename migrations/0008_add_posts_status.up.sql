@@ -0,0 +1,14 @@
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'in_review', 'published', 'archived')),
+    ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+
+-- everything written before the workflow existed was already live, the
+-- backfill is no edit and leaves updated_at as it was
+ALTER TABLE posts DISABLE TRIGGER posts_set_updated_at;
+
+UPDATE posts SET status = 'published', published_at = created_at;
+
+ALTER TABLE posts ENABLE TRIGGER posts_set_updated_at;
+
+CREATE INDEX IF NOT EXISTS posts_status_created_at_idx ON posts (status, created_at DESC, id DESC);
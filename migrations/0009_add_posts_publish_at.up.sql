@@ -0,0 +1,5 @@
+-- publish_at is the moment a draft or a reviewed post goes live, the
+-- scheduler clears it once the post is published
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS posts_publish_at_idx ON posts (publish_at) WHERE publish_at IS NOT NULL;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS post_revisions
+(
+    id         SERIAL NOT NULL PRIMARY KEY,
+    post_id    INT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+    revision   INT NOT NULL,
+    title      VARCHAR(255) NOT NULL,
+    content    TEXT NOT NULL,
+    edited_by  INT REFERENCES authors (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (post_id, revision)
+);
+
+-- the current text of existing posts becomes their first revision
+INSERT INTO post_revisions (post_id, revision, title, content, edited_by, created_at)
+SELECT id, 1, title, content, author_id, updated_at
+FROM posts;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS posts_deleted_at_idx;
+
+-- trashed posts would come back to life without the column
+DELETE FROM posts WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS deleted_at;
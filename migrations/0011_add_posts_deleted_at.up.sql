@@ -0,0 +1,4 @@
+-- deleted_at is set while a post is in the trash
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS posts_deleted_at_idx ON posts (deleted_at) WHERE deleted_at IS NOT NULL;
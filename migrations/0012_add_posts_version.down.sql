@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS posts_bump_version ON posts;
+DROP FUNCTION IF EXISTS bump_version();
+
+ALTER TABLE posts DROP COLUMN IF EXISTS version;
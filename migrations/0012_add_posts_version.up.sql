@@ -0,0 +1,15 @@
+-- version is bumped by every change of a post and guards updates against
+-- lost writes
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION bump_version() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER posts_bump_version
+    BEFORE UPDATE ON posts
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_version();
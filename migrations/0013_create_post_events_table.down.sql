@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS posts_record_event ON posts;
+DROP FUNCTION IF EXISTS record_post_event();
+
+DROP TABLE IF EXISTS post_events;
@@ -0,0 +1,62 @@
+-- post_events records every change of a post for the Watch feed, watchers
+-- are woken up by NOTIFY post_events and read the events from here
+CREATE TABLE IF NOT EXISTS post_events
+(
+    id         BIGSERIAL NOT NULL PRIMARY KEY,
+    post_id    INT NOT NULL,
+    author_id  INT,
+    type       VARCHAR(16) NOT NULL CHECK (type IN ('created', 'updated', 'deleted')),
+    -- published tells whether the post was public before or after the change
+    published  BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS post_events_created_at_idx ON post_events (created_at);
+
+-- trashing a post reads as a deletion and restoring it as a creation, purging
+-- a trashed post was already reported when it was trashed
+CREATE OR REPLACE FUNCTION record_post_event() RETURNS TRIGGER AS $$
+DECLARE
+    event_type VARCHAR(16);
+    is_public  BOOLEAN := FALSE;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        event_type := 'created';
+    ELSIF TG_OP = 'DELETE' THEN
+        IF OLD.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+        event_type := 'deleted';
+    ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+        event_type := 'deleted';
+    ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+        event_type := 'created';
+    ELSIF NEW.deleted_at IS NULL THEN
+        event_type := 'updated';
+    ELSE
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP <> 'INSERT' THEN
+        is_public := OLD.status = 'published';
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO post_events (post_id, author_id, type, published)
+        VALUES (OLD.id, OLD.author_id, event_type, is_public);
+    ELSE
+        INSERT INTO post_events (post_id, author_id, type, published)
+        VALUES (NEW.id, NEW.author_id, event_type, is_public OR NEW.status = 'published');
+    END IF;
+
+    -- an empty payload folds all notifications of a transaction into one
+    PERFORM pg_notify('post_events', '');
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER posts_record_event
+    AFTER INSERT OR UPDATE OR DELETE ON posts
+    FOR EACH ROW
+    EXECUTE FUNCTION record_post_event();
@@ -0,0 +1,51 @@
+-- back to the trigger of 0013, which records no payload
+CREATE OR REPLACE FUNCTION record_post_event() RETURNS TRIGGER AS $$
+DECLARE
+    event_type VARCHAR(16);
+    is_public  BOOLEAN := FALSE;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        event_type := 'created';
+    ELSIF TG_OP = 'DELETE' THEN
+        IF OLD.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+        event_type := 'deleted';
+    ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+        event_type := 'deleted';
+    ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+        event_type := 'created';
+    ELSIF NEW.deleted_at IS NULL THEN
+        event_type := 'updated';
+    ELSE
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP <> 'INSERT' THEN
+        is_public := OLD.status = 'published';
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO post_events (post_id, author_id, type, published)
+        VALUES (OLD.id, OLD.author_id, event_type, is_public);
+    ELSE
+        INSERT INTO post_events (post_id, author_id, type, published)
+        VALUES (NEW.id, NEW.author_id, event_type, is_public OR NEW.status = 'published');
+    END IF;
+
+    -- an empty payload folds all notifications of a transaction into one
+    PERFORM pg_notify('post_events', '');
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS post_events_pending_idx;
+
+ALTER TABLE post_events
+    DROP COLUMN IF EXISTS payload,
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS delivered_at,
+    DROP COLUMN IF EXISTS failed_at;
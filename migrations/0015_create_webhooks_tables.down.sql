@@ -0,0 +1,61 @@
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+
+-- back to the trigger of 0014, which records no previous status
+CREATE OR REPLACE FUNCTION record_post_event() RETURNS TRIGGER AS $$
+DECLARE
+    event_type VARCHAR(16);
+    is_public  BOOLEAN := FALSE;
+    post       RECORD;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        event_type := 'created';
+    ELSIF TG_OP = 'DELETE' THEN
+        IF OLD.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+        event_type := 'deleted';
+    ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+        event_type := 'deleted';
+    ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+        event_type := 'created';
+    ELSIF NEW.deleted_at IS NULL THEN
+        event_type := 'updated';
+    ELSE
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP <> 'INSERT' THEN
+        is_public := OLD.status = 'published';
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        post := OLD;
+    ELSE
+        post := NEW;
+        is_public := is_public OR NEW.status = 'published';
+    END IF;
+
+    INSERT INTO post_events (post_id, author_id, type, published, payload)
+    VALUES (post.id, post.author_id, event_type, is_public, jsonb_build_object(
+        'id', post.id,
+        'title', post.title,
+        'content', post.content,
+        'author_id', post.author_id,
+        'status', post.status,
+        'published_at', post.published_at,
+        'publish_at', post.publish_at,
+        'created_at', post.created_at,
+        'updated_at', post.updated_at,
+        'deleted_at', post.deleted_at,
+        'version', post.version
+    ));
+
+    -- an empty payload folds all notifications of a transaction into one
+    PERFORM pg_notify('post_events', '');
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE post_events DROP COLUMN IF EXISTS previous_status;
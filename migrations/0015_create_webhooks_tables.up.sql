@@ -0,0 +1,98 @@
+-- previous_status is the status of the post before the change, so webhooks
+-- can tell a post that got published from one that was merely edited
+ALTER TABLE post_events
+    ADD COLUMN IF NOT EXISTS previous_status VARCHAR(16);
+
+CREATE OR REPLACE FUNCTION record_post_event() RETURNS TRIGGER AS $$
+DECLARE
+    event_type VARCHAR(16);
+    is_public  BOOLEAN := FALSE;
+    old_status VARCHAR(16);
+    post       RECORD;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        event_type := 'created';
+    ELSIF TG_OP = 'DELETE' THEN
+        IF OLD.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+        event_type := 'deleted';
+    ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+        event_type := 'deleted';
+    ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+        event_type := 'created';
+    ELSIF NEW.deleted_at IS NULL THEN
+        event_type := 'updated';
+    ELSE
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP <> 'INSERT' THEN
+        is_public := OLD.status = 'published';
+        old_status := OLD.status;
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        post := OLD;
+    ELSE
+        post := NEW;
+        is_public := is_public OR NEW.status = 'published';
+    END IF;
+
+    INSERT INTO post_events (post_id, author_id, type, published, previous_status, payload)
+    VALUES (post.id, post.author_id, event_type, is_public, old_status, jsonb_build_object(
+        'id', post.id,
+        'title', post.title,
+        'content', post.content,
+        'author_id', post.author_id,
+        'status', post.status,
+        'published_at', post.published_at,
+        'publish_at', post.publish_at,
+        'created_at', post.created_at,
+        'updated_at', post.updated_at,
+        'deleted_at', post.deleted_at,
+        'version', post.version
+    ));
+
+    -- an empty payload folds all notifications of a transaction into one
+    PERFORM pg_notify('post_events', '');
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- webhook_subscriptions are the partner endpoints notified of post events,
+-- deliveries are signed with the secret of the subscription
+CREATE TABLE IF NOT EXISTS webhook_subscriptions
+(
+    id         SERIAL NOT NULL PRIMARY KEY,
+    url        TEXT NOT NULL,
+    secret     TEXT NOT NULL,
+    events     TEXT[] NOT NULL,
+    created_by INT REFERENCES authors (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- webhook_deliveries is both the queue of the webhook workers and the
+-- delivery log of every subscription. Test deliveries have no event_id.
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              BIGSERIAL NOT NULL PRIMARY KEY,
+    subscription_id INT NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id        BIGINT,
+    event           VARCHAR(32) NOT NULL,
+    payload         JSONB NOT NULL,
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts        INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    response_status INT,
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at    TIMESTAMPTZ,
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_subscription_idx ON webhook_deliveries (subscription_id, id DESC);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_created_at_idx ON webhook_deliveries (created_at);
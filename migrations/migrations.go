@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migrations of the service, applied by
+// internal/migrate. Every version has an up file and usually a down file,
+// named <version>_<name>.up.sql and <version>_<name>.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,19 @@
+package backoff
+
+import "time"
+
+// Exponential returns the delay before retrying after the given number of
+// attempts: min after the first one, doubling with every further attempt up
+// to max.
+func Exponential(attempts int, min, max time.Duration) time.Duration {
+	delay := min
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		return max
+	}
+
+	return delay
+}
@@ -0,0 +1,17 @@
+package backoff_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/pkg/backoff"
+)
+
+func TestExponential(t *testing.T) {
+	assert.Equal(t, time.Second, backoff.Exponential(0, time.Second, time.Hour))
+	assert.Equal(t, time.Second, backoff.Exponential(1, time.Second, time.Hour))
+	assert.Equal(t, 8*time.Second, backoff.Exponential(4, time.Second, time.Hour))
+	assert.Equal(t, time.Hour, backoff.Exponential(50, time.Second, time.Hour))
+}
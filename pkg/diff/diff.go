@@ -0,0 +1,75 @@
+package diff
+
+import "strings"
+
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+type Line struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines returns the line-level edit script turning a into b. It keeps the
+// longest common subsequence of lines and reports deletions before
+// insertions at every change.
+func Lines(a, b string) []Line {
+	x, y := split(a), split(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	res := make([]Line, 0, len(x)+len(y))
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			res = append(res, Line{Op: Equal, Text: x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			res = append(res, Line{Op: Delete, Text: x[i]})
+			i++
+		default:
+			res = append(res, Line{Op: Insert, Text: y[j]})
+			j++
+		}
+	}
+
+	for ; i < len(x); i++ {
+		res = append(res, Line{Op: Delete, Text: x[i]})
+	}
+
+	for ; j < len(y); j++ {
+		res = append(res, Line{Op: Insert, Text: y[j]})
+	}
+
+	return res
+}
+
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, "\n")
+}
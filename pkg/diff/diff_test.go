@@ -0,0 +1,26 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kokhno-nikolay/news/pkg/diff"
+)
+
+func TestLines(t *testing.T) {
+	a := "first\nsecond\nthird"
+	b := "first\nchanged\nthird\nfourth"
+
+	assert.Equal(t, []diff.Line{
+		{Op: diff.Equal, Text: "first"},
+		{Op: diff.Delete, Text: "second"},
+		{Op: diff.Insert, Text: "changed"},
+		{Op: diff.Equal, Text: "third"},
+		{Op: diff.Insert, Text: "fourth"},
+	}, diff.Lines(a, b))
+
+	assert.Equal(t, []diff.Line{{Op: diff.Insert, Text: "new"}}, diff.Lines("", "new"))
+	assert.Equal(t, []diff.Line{{Op: diff.Delete, Text: "old"}}, diff.Lines("old", ""))
+	assert.Empty(t, diff.Lines("", ""))
+}
@@ -2,4 +2,20 @@ package errors
 
 import "errors"
 
-var ErrNotFound = errors.New("entity not found")
+var (
+	ErrNotFound          = errors.New("entity not found")
+	ErrAlreadyExists     = errors.New("entity already exists")
+	ErrUnauthenticated   = errors.New("authentication required")
+	ErrPermissionDenied  = errors.New("permission denied")
+	ErrInvalidArgument   = errors.New("invalid argument")
+	ErrInvalidPageToken  = errors.New("invalid page token")
+	ErrInvalidSortField  = errors.New("invalid sort field")
+	ErrInvalidSortOrder  = errors.New("invalid sort order")
+	ErrInvalidStatus     = errors.New("invalid post status")
+	ErrInvalidUpdateMask = errors.New("invalid update mask")
+	ErrBatchAborted      = errors.New("not applied, another item of the batch failed")
+	ErrEventsExpired     = errors.New("events to resume from are no longer kept")
+
+	ErrInvalidStatusTransition = errors.New("invalid post status transition")
+	ErrVersionConflict         = errors.New("version conflict")
+)
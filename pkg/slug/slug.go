@@ -0,0 +1,27 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Make turns a human readable name into a lowercase, dash separated slug.
+// Letters of any script are kept, so "Новини Києва" becomes "новини-києва".
+func Make(s string) string {
+	var b strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+
+	return b.String()
+}